@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/rule"
+)
+
+var checkRules = flag.String("rules", "", "Rules file used in check.")
+
+func readRules() rule.RuleSet {
+	if *checkRules == "" {
+		fail(errors.New("no rules file, use -rules"))
+	}
+	f, err := os.Open(*checkRules)
+	fail(err)
+	defer f.Close()
+	rules, err := rule.Parse(f)
+	fail(err)
+	return rules
+}
+
+func runCheck() {
+	rules := readRules()
+	profiler := newProfiler()
+	profiler.Init()
+	pkgs := loadPackages()
+	profiler.PkgLoaded(pkgs)
+	var (
+		searcher = newSearcher(
+			pkgs,
+			extractDefSetList(pkgs),
+			searcherOptions()...,
+		)
+		writer = display.NewCheckWriter(os.Stdout, rules)
+	)
+	write(profiler, searcher, writer)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+)
+
+var closureTop = flag.Int("closure.top", 10, "Number of definitions and packages displayed in closure when type is text.")
+
+func newClosureWriter() display.Writer {
+	switch *outputType {
+	case "dot":
+		opt := append(writerOptions(), display.WithWriterClosure(true))
+		if *useStat {
+			return display.NewPackageDotWriter(os.Stdout, opt...)
+		}
+		return display.NewNodeDotWriter(os.Stdout, opt...)
+	case "json":
+		return display.NewClosureJSONWriter(os.Stdout)
+	case "text":
+		return display.NewClosureWriter(os.Stdout, *closureTop)
+	default:
+		fail(fmt.Errorf("type %s is not available for closure", *outputType))
+		return nil
+	}
+}
+
+func runClosure() {
+	writer := newClosureWriter()
+	profiler := newProfiler()
+	profiler.Init()
+	pkgs := loadPackages()
+	profiler.PkgLoaded(pkgs)
+	searcher := newSearcher(
+		pkgs,
+		extractDefSetList(pkgs),
+		searcherOptions()...,
+	)
+	write(profiler, searcher, writer)
+}
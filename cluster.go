@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+)
+
+func newClusterWriter() display.Writer {
+	switch *outputType {
+	case "dot":
+		return display.NewNodeDotWriter(os.Stdout, append(writerOptions(), display.WithWriterCluster(true))...)
+	case "text":
+		return display.NewClusterWriter(os.Stdout)
+	default:
+		fail(fmt.Errorf("type %s is not available for cluster", *outputType))
+		return nil
+	}
+}
+
+func runCluster() {
+	writer := newClusterWriter()
+	profiler := newProfiler()
+	profiler.Init()
+	pkgs := loadPackages()
+	profiler.PkgLoaded(pkgs)
+	searcher := newSearcher(
+		pkgs,
+		extractDefSetList(pkgs),
+		searcherOptions()...,
+	)
+	write(profiler, searcher, writer)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/util"
+)
+
+var cohesionMin = flag.Int("cohesion.min", 1, "Minimum LCOM4 of the types displayed in cohesion.")
+
+func runCohesion() {
+	profiler := newProfiler()
+	profiler.Init()
+	pkgs := loadPackages()
+	profiler.PkgLoaded(pkgs)
+	var (
+		defSetList       = extractDefSetList(pkgs)
+		defNodeExtractor = search.NewDefNodeExtractor(search.NewObjExtractor())
+		defs             []search.DefNode
+	)
+	for _, defSet := range defSetList {
+		defs = append(defs, defNodeExtractor.Extract(defSet)...)
+	}
+	var (
+		objNameRegexp = util.NewRegexpPair(compileRegex(*acceptNameRegex), compileRegex(*denyNameRegex))
+		// the name filter selects the methods, the fields are always searched
+		filter   = search.DefSetFilter(defSetList).And(search.ObjectNameFilter(objNameRegexp)).Or(search.FieldFilter(defSetList))
+		searcher = newSearcherWithFilter(
+			pkgs,
+			defSetList,
+			filter,
+			append(searcherOptions(),
+				// fields are mostly private
+				search.WithUseSearcherSearchPrivate(true),
+				search.WithUseSearcherSearchForeign(false),
+				search.WithUseSearcherSearchUniverse(false),
+				search.WithUseSearcherObjNameRegexp(nil),
+				// the uses of the fields and the methods are in the same package
+				search.WithUseSearcherIgnorePkgSelfloop(false),
+			)...,
+		)
+		writer = display.NewCohesionWriter(os.Stdout, defs, *cohesionMin)
+	)
+	write(profiler, searcher, writer)
+}
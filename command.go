@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"os"
+)
+
+type command struct {
+	name string
+	desc string
+	run  func()
+}
+
+var commands = []*command{
+	{
+		name: "deadcode",
+		desc: "Report exported definitions that are never referred from the other packages.",
+		run:  runDeadcode,
+	},
+	{
+		name: "rank",
+		desc: "Report the top definitions by in/out degree, pagerank and betweenness.",
+		run:  runRank,
+	},
+	{
+		name: "check",
+		desc: "Check the dependencies against the rules file and fail if violated.",
+		run:  runCheck,
+	},
+	{
+		name: "layer",
+		desc: "Report the layers of the packages and the dependencies that violate them.",
+		run:  runLayer,
+	},
+	{
+		name: "diff",
+		desc: "Compare the dependencies of two directories or git revisions.",
+		run:  runDiff,
+	},
+	{
+		name: "gate",
+		desc: "Compare the dependencies with the baseline file and fail on regressions.",
+		run:  runGate,
+	},
+	{
+		name: "cluster",
+		desc: "Suggest the clusters of the definitions per package by community detection.",
+		run:  runCluster,
+	},
+	{
+		name: "cohesion",
+		desc: "Report the LCOM4 of the named types, the number of the unrelated groups of the methods.",
+		run:  runCohesion,
+	},
+	{
+		name: "closure",
+		desc: "Report the sizes of the transitive dependency and dependent closures.",
+		run:  runClosure,
+	},
+	{
+		name: "schema",
+		desc: "Print the JSON Schema of the json-stat output.",
+		run:  runSchema,
+	},
+	{
+		name: "render",
+		desc: "Render the output of -type json from the files or stdin instead of analyzing the source.",
+		run:  runRender,
+	},
+}
+
+var graphCommand = &command{
+	name: "graph",
+	desc: "Generate definitions and references graph.",
+	run:  runGraph,
+}
+
+func findCommand(name string) (*command, bool) {
+	if name == graphCommand.name {
+		return graphCommand, true
+	}
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// parseCommand parses the command line arguments.
+// The first argument selects the command, the graph command is selected if it is not a command name.
+func parseCommand() *command {
+	if len(os.Args) > 1 {
+		if c, ok := findCommand(os.Args[1]); ok {
+			fail(flag.CommandLine.Parse(os.Args[2:]))
+			return c
+		}
+	}
+	flag.Parse()
+	return graphCommand
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/load"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/util"
+)
+
+var (
+	deadcodeTest    = flag.Bool("deadcode.test", false, "Count references from test files in deadcode.")
+	deadcodeMain    = flag.Bool("deadcode.main", true, "Treat main packages as roots in deadcode.")
+	deadcodeRootPkg = flag.String("deadcode.root", "", "Treat packages whose path matches this as roots in deadcode.")
+)
+
+func runDeadcode() {
+	profiler := newProfiler()
+	profiler.Init()
+	pkgs := loadPackages(load.WithLoaderTests(*deadcodeTest))
+	profiler.PkgLoaded(pkgs)
+	var (
+		defSetList       = extractDefSetList(pkgs)
+		defNodeExtractor = search.NewDefNodeExtractor(search.NewObjExtractor())
+		defs             []search.DefNode
+	)
+	for _, defSet := range defSetList {
+		defs = append(defs, defNodeExtractor.Extract(defSet)...)
+	}
+	var (
+		pkgNameRegexp = util.NewRegexpPair(compileRegex(*acceptPkgRegex), compileRegex(*denyPkgRegex))
+		objNameRegexp = util.NewRegexpPair(compileRegex(*acceptNameRegex), compileRegex(*denyNameRegex))
+		// count the uses from all the packages, the name filters select the definitions to report
+		searcher = newSearcher(
+			pkgs,
+			defSetList,
+			append(searcherOptions(),
+				search.WithUseSearcherSearchPrivate(false),
+				search.WithUseSearcherSearchForeign(false),
+				search.WithUseSearcherSearchUniverse(false),
+				search.WithUseSearcherIgnorePkgSelfloop(true),
+				search.WithUseSearcherPkgNameRegexp(nil),
+				search.WithUseSearcherObjNameRegexp(nil),
+			)...,
+		)
+		writer = display.NewDeadcodeWriter(
+			os.Stdout,
+			defs,
+			display.WithDeadcodeWriterCountTest(*deadcodeTest),
+			display.WithDeadcodeWriterRootMain(*deadcodeMain),
+			display.WithDeadcodeWriterRootPkgRegexp(compileRegex(*deadcodeRootPkg)),
+			display.WithDeadcodeWriterPkgNameRegexp(pkgNameRegexp),
+			display.WithDeadcodeWriterObjNameRegexp(objNameRegexp),
+		)
+	)
+	write(profiler, searcher, writer)
+}
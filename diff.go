@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/load"
+	"github.com/berquerant/gotypegraph/logger"
+	"github.com/berquerant/gotypegraph/snapshot"
+)
+
+var (
+	diffBase = flag.String("diff.base", "", "Directory or git revision to be compared in diff.")
+	diffHead = flag.String("diff.head", "", "Directory or git revision to compare in diff. The current directory if empty.")
+)
+
+func newDiffWriter() display.DiffWriter {
+	switch *outputType {
+	case "dot":
+		return display.NewDotDiffWriter(os.Stdout, *useStat)
+	case "json":
+		return display.NewJSONDiffWriter(os.Stdout)
+	case "text":
+		return display.NewTextDiffWriter(os.Stdout)
+	default:
+		fail(fmt.Errorf("type %s is not available for diff", *outputType))
+		return nil
+	}
+}
+
+func runDiff() {
+	if *diffBase == "" {
+		fail(errors.New("no base, use -diff.base"))
+	}
+	writer := newDiffWriter()
+	before, err := buildSnapshot(*diffBase)
+	fail(err)
+	after, err := buildSnapshot(*diffHead)
+	fail(err)
+	fail(writer.Write(snapshot.NewDiff(before, after)))
+}
+
+// sourceDir returns the directory of the source.
+// The source is a directory or a git revision, the current directory if empty.
+func sourceDir(src string) (string, func(), error) {
+	if src == "" {
+		return "", func() {}, nil
+	}
+	if fi, err := os.Stat(src); err == nil && fi.IsDir() {
+		return src, func() {}, nil
+	}
+	wt, err := load.NewWorktree(src)
+	if err != nil {
+		return "", nil, err
+	}
+	return wt.Dir(), func() {
+		if err := wt.Close(); err != nil {
+			logger.Warnf("%v", err)
+		}
+	}, nil
+}
+
+func buildSnapshot(src string) (*snapshot.Snapshot, error) {
+	dir, cleanup, err := sourceDir(src)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	logger.Infof("Load packages from %q", src)
+	pkgs, err := load.New(load.WithLoaderDir(dir)).Load(flag.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("%d packages loaded", len(pkgs))
+	var (
+		searcher = newSearcher(
+			pkgs,
+			extractDefSetList(pkgs),
+			searcherOptions()...,
+		)
+		builder = snapshot.NewBuilder()
+	)
+	for result := range searcher.Search() {
+		builder.Add(result)
+	}
+	return builder.Build(), nil
+}
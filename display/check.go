@@ -0,0 +1,84 @@
+package display
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+
+	"github.com/berquerant/gotypegraph/rule"
+	"github.com/berquerant/gotypegraph/search"
+)
+
+// NewCheckWriter returns a writer that reports the uses denied by the rules.
+// Flush fails if any violations are found.
+func NewCheckWriter(w io.Writer, rules rule.RuleSet) Writer {
+	return &checkWriter{
+		w:          w,
+		rules:      rules,
+		violations: []*checkViolation{},
+	}
+}
+
+type (
+	checkWriter struct {
+		w          io.Writer
+		rules      rule.RuleSet
+		violations []*checkViolation
+	}
+
+	checkViolation struct {
+		rule     *rule.Rule
+		use      search.Use
+		position token.Position
+	}
+)
+
+func (s *checkWriter) Write(node search.Use) error {
+	var (
+		ref = node.Ref()
+		def = node.Def()
+	)
+	r, denied := s.rules.Denied(rule.NewTarget(ref), rule.NewTarget(def))
+	if !denied {
+		return nil
+	}
+	var position token.Position
+	if pkg := ref.Pkg().Pkg(); pkg != nil && pkg.Fset != nil {
+		position = pkg.Fset.Position(ref.Ident().Pos())
+	}
+	s.violations = append(s.violations, &checkViolation{
+		rule:     r,
+		use:      node,
+		position: position,
+	})
+	return nil
+}
+
+func (s *checkWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("CheckWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *checkWriter) flush() error {
+	sort.Slice(s.violations, func(i, j int) bool {
+		l, r := s.violations[i].position, s.violations[j].position
+		if l.Filename != r.Filename {
+			return l.Filename < r.Filename
+		}
+		return l.Offset < r.Offset
+	})
+	for _, x := range s.violations {
+		if _, err := fmt.Fprintf(s.w, "%s\t%s\t%s -> %s\n",
+			x.position, x.rule.Name, nodeFullName(x.use.Ref()), nodeFullName(x.use.Def()),
+		); err != nil {
+			return err
+		}
+	}
+	if n := len(s.violations); n > 0 {
+		return fmt.Errorf("%d violations", n)
+	}
+	return nil
+}
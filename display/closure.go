@@ -0,0 +1,150 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+// NewClosureWriter returns a writer that reports the top n nodes and packages
+// by the size of the transitive dependent closure.
+// Reports all if n is not positive.
+func NewClosureWriter(w io.Writer, n int) Writer {
+	return &closureWriter{
+		w:               w,
+		n:               n,
+		nodeClosureCalc: stat.NewNodeClosureCalculator(),
+		pkgClosureCalc:  stat.NewPkgClosureCalculator(),
+	}
+}
+
+type closureWriter struct {
+	w               io.Writer
+	n               int
+	nodeClosureCalc stat.NodeClosureCalculator
+	pkgClosureCalc  stat.PkgClosureCalculator
+}
+
+func (s *closureWriter) Write(node search.Use) error {
+	var (
+		ref = stat.NewNode(node.Ref())
+		def = stat.NewNode(node.Def())
+	)
+	s.nodeClosureCalc.Add(ref, def)
+	s.pkgClosureCalc.Add(ref.Pkg(), def.Pkg())
+	return nil
+}
+
+func (s *closureWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("ClosureWriter: %w", err)
+	}
+	return nil
+}
+
+type closureRow struct {
+	name         string
+	dependencies int
+	dependents   int
+}
+
+func (s *closureWriter) flush() error {
+	var (
+		nodeRows []*closureRow
+		pkgRows  []*closureRow
+	)
+	for _, x := range s.nodeClosureCalc.Result().Closures() {
+		nodeRows = append(nodeRows, &closureRow{
+			name:         nodeFullName(x.Node().Node()),
+			dependencies: x.Dependencies(),
+			dependents:   x.Dependents(),
+		})
+	}
+	for _, x := range s.pkgClosureCalc.Result().Closures() {
+		pkgRows = append(pkgRows, &closureRow{
+			name:         x.Pkg().ID(),
+			dependencies: x.Dependencies(),
+			dependents:   x.Dependents(),
+		})
+	}
+	if err := s.writeRows("nodes", nodeRows); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(s.w); err != nil {
+		return err
+	}
+	return s.writeRows("packages", pkgRows)
+}
+
+func (s *closureWriter) writeRows(title string, rows []*closureRow) error {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].dependents != rows[j].dependents {
+			return rows[i].dependents > rows[j].dependents
+		}
+		if rows[i].dependencies != rows[j].dependencies {
+			return rows[i].dependencies > rows[j].dependencies
+		}
+		return rows[i].name < rows[j].name
+	})
+	if s.n > 0 && s.n < len(rows) {
+		rows = rows[:s.n]
+	}
+	if _, err := fmt.Fprintf(s.w, "# %s\n", title); err != nil {
+		return err
+	}
+	for i, x := range rows {
+		if _, err := fmt.Fprintf(s.w, "%d\t%d\t%d\t%s\n", i+1, x.dependents, x.dependencies, x.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewClosureJSONWriter returns a writer that writes the sizes of the transitive closures
+// of the nodes and the packages as a JSON.
+func NewClosureJSONWriter(w io.Writer) Writer {
+	return &closureJSONWriter{
+		w:               w,
+		nodeClosureCalc: stat.NewNodeClosureCalculator(),
+		pkgClosureCalc:  stat.NewPkgClosureCalculator(),
+	}
+}
+
+type closureJSONWriter struct {
+	w               io.Writer
+	nodeClosureCalc stat.NodeClosureCalculator
+	pkgClosureCalc  stat.PkgClosureCalculator
+}
+
+func (s *closureJSONWriter) Write(node search.Use) error {
+	var (
+		ref = stat.NewNode(node.Ref())
+		def = stat.NewNode(node.Def())
+	)
+	s.nodeClosureCalc.Add(ref, def)
+	s.pkgClosureCalc.Add(ref.Pkg(), def.Pkg())
+	return nil
+}
+
+func (s *closureJSONWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("ClosureJSONWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *closureJSONWriter) flush() error {
+	b, err := json.Marshal(map[string]interface{}{
+		"nodes": s.nodeClosureCalc.Result(),
+		"pkgs":  s.pkgClosureCalc.Result(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.w, string(b))
+	return err
+}
@@ -0,0 +1,68 @@
+package display
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+// NewClusterWriter returns a writer that reports the clusters of the nodes per package,
+// the candidates for splitting the package.
+func NewClusterWriter(w io.Writer) Writer {
+	return &clusterWriter{
+		w:       w,
+		depCalc: stat.NewNodeDepCalculator(),
+	}
+}
+
+type clusterWriter struct {
+	w       io.Writer
+	depCalc stat.NodeDepCalculator
+}
+
+func (s *clusterWriter) Write(node search.Use) error {
+	s.depCalc.Add(stat.NewNode(node.Ref()), stat.NewNode(node.Def()))
+	return nil
+}
+
+func (s *clusterWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("ClusterWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *clusterWriter) flush() error {
+	var (
+		pkgID string
+		index int
+	)
+	for _, cluster := range stat.NewNodeClusters(s.depCalc.Result()) {
+		if id := cluster.Pkg().ID(); id != pkgID {
+			if pkgID != "" {
+				if _, err := fmt.Fprintln(s.w); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(s.w, "# %s\n", id); err != nil {
+				return err
+			}
+			pkgID = id
+			index = 0
+		}
+		if _, err := fmt.Fprintf(s.w, "%d\t%.6f\t%d\t%d\n",
+			index, cluster.Cohesion(), cluster.Weight(), cluster.CutWeight(),
+		); err != nil {
+			return err
+		}
+		for _, x := range cluster.Nodes() {
+			if _, err := fmt.Fprintf(s.w, "\t%s\n", nodeNameWithRecv(x.Node())); err != nil {
+				return err
+			}
+		}
+		index++
+	}
+	return nil
+}
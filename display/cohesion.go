@@ -0,0 +1,63 @@
+package display
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+// NewCohesionWriter returns a writer that reports the LCOM4 of the named types
+// whose methods are in defs, with the components of the methods and the fields.
+// The types whose LCOM4 is less than min are not reported.
+func NewCohesionWriter(w io.Writer, defs []search.DefNode, min int) Writer {
+	calc := stat.NewTypeCohesionCalculator()
+	for _, x := range defs {
+		calc.AddMethod(stat.NewNode(x))
+	}
+	return &cohesionWriter{
+		w:    w,
+		min:  min,
+		calc: calc,
+	}
+}
+
+type cohesionWriter struct {
+	w    io.Writer
+	min  int
+	calc stat.TypeCohesionCalculator
+}
+
+func (s *cohesionWriter) Write(node search.Use) error {
+	s.calc.Add(stat.NewNode(node.Ref()), stat.NewNode(node.Def()))
+	return nil
+}
+
+func (s *cohesionWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("CohesionWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *cohesionWriter) flush() error {
+	for _, x := range s.calc.Result() {
+		if x.LCOM() < s.min {
+			continue
+		}
+		if _, err := fmt.Fprintf(s.w, "%s.%s\t%d\n", x.Pkg().ID(), x.Name(), x.LCOM()); err != nil {
+			return err
+		}
+		for i, c := range x.Components() {
+			for _, node := range c {
+				if _, err := fmt.Fprintf(s.w, "\t%d\t%s\t%s\n",
+					i, node.Node().Type(), nodeNameWithRecv(node.Node()),
+				); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package display
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/search"
+)
+
+// NewCypherWriter returns a writer that writes the definitions as the Cypher statements for Neo4j.
+// The definitions are the nodes labeled Definition and their node types, e.g. Definition:Func,
+// connected to the Package nodes by IN_PACKAGE and to each other by USES that has weight and kind.
+// The statements use MERGE so they can be run more than once.
+func NewCypherWriter(w io.Writer) Writer {
+	return &cypherWriter{
+		w:    w,
+		calc: newNodeElementsCalculator(true),
+	}
+}
+
+type cypherWriter struct {
+	w    io.Writer
+	calc graphElementsCalculator
+}
+
+func (s *cypherWriter) Write(node search.Use) error {
+	s.calc.Add(node)
+	return nil
+}
+
+func (s *cypherWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("CypherWriter: %w", err)
+	}
+	return nil
+}
+
+var cypherStringReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+)
+
+func cypherString(s string) string { return "'" + cypherStringReplacer.Replace(s) + "'" }
+
+// cypherLabel returns the label of the node type, e.g. Func for func.
+func cypherLabel(typ string) string {
+	if typ == "" {
+		return "Unknown"
+	}
+	return strings.ToUpper(typ[:1]) + typ[1:]
+}
+
+// cypherProperties returns the properties in SET clause.
+func cypherProperties(v string, props map[string]string) string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	xs := make([]string, len(keys))
+	for i, k := range keys {
+		xs[i] = fmt.Sprintf("%s.%s = %s", v, k, props[k])
+	}
+	return strings.Join(xs, ", ")
+}
+
+func (s *cypherWriter) flush() error {
+	var (
+		elements = s.calc.Result()
+		nodes    = make(map[string]*graphElementNode, len(elements.nodes))
+		w        = bufio.NewWriter(s.w)
+	)
+	for _, x := range elements.nodes {
+		nodes[x.id] = x
+	}
+
+	fmt.Fprintln(w, "CREATE CONSTRAINT IF NOT EXISTS FOR (p:Package) REQUIRE p.path IS UNIQUE;")
+	fmt.Fprintln(w, "CREATE CONSTRAINT IF NOT EXISTS FOR (d:Definition) REQUIRE d.key IS UNIQUE;")
+	for _, x := range elements.parents {
+		fmt.Fprintf(w, "MERGE (p:Package {path: %s}) SET p.name = %s;\n", cypherString(x.pkg), cypherString(x.name))
+	}
+	for _, x := range elements.nodes {
+		props := map[string]string{
+			"name":    cypherString(x.name),
+			"type":    cypherString(x.typ),
+			"package": cypherString(x.pkg),
+			"ref":     strconv.Itoa(x.ref),
+			"def":     strconv.Itoa(x.def),
+			"uniqref": strconv.Itoa(x.uniqRef),
+			"uniqdef": strconv.Itoa(x.uniqDef),
+		}
+		if x.recv != "" {
+			props["recv"] = cypherString(x.recv)
+		}
+		if x.position != "" {
+			props["position"] = cypherString(x.position)
+		}
+		for k, v := range x.metrics {
+			props[k] = strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		fmt.Fprintf(w, "MERGE (d:Definition {key: %s}) SET d:%s, %s;\n",
+			cypherString(x.id), cypherLabel(x.typ), cypherProperties("d", props))
+		fmt.Fprintf(w, "MATCH (d:Definition {key: %s}), (p:Package {path: %s}) MERGE (d)-[:IN_PACKAGE]->(p);\n",
+			cypherString(x.id), cypherString(x.parent))
+	}
+	for _, x := range elements.edges {
+		fmt.Fprintf(w, "MATCH (r:Definition {key: %s}), (d:Definition {key: %s}) MERGE (r)-[u:USES]->(d) SET u.weight = %d, u.kind = %s;\n",
+			cypherString(x.source), cypherString(x.target), x.weight, cypherString(nodes[x.target].typ))
+	}
+	return w.Flush()
+}
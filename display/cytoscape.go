@@ -0,0 +1,112 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/berquerant/gotypegraph/search"
+)
+
+// NewNodeCytoscapeWriter returns a writer that writes the definitions as the elements JSON of Cytoscape.js,
+// the packages are the compound parents of the definitions.
+func NewNodeCytoscapeWriter(w io.Writer) Writer {
+	return &cytoscapeWriter{
+		w:    w,
+		calc: newNodeElementsCalculator(true),
+	}
+}
+
+// NewPackageCytoscapeWriter returns a writer that writes the packages as the elements JSON of Cytoscape.js.
+func NewPackageCytoscapeWriter(w io.Writer) Writer {
+	return &cytoscapeWriter{
+		w:    w,
+		calc: newPackageElementsCalculator(true),
+	}
+}
+
+type cytoscapeWriter struct {
+	w    io.Writer
+	calc graphElementsCalculator
+}
+
+func (s *cytoscapeWriter) Write(node search.Use) error {
+	s.calc.Add(node)
+	return nil
+}
+
+func (s *cytoscapeWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("CytoscapeWriter: %w", err)
+	}
+	return nil
+}
+
+type cytoscapeElement struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+func (s *cytoscapeWriter) flush() error {
+	var (
+		elements = s.calc.Result()
+		nodes    = []*cytoscapeElement{}
+		edges    = []*cytoscapeElement{}
+	)
+	for _, x := range elements.parents {
+		nodes = append(nodes, &cytoscapeElement{
+			Data: map[string]interface{}{
+				"id":    x.id,
+				"label": x.label,
+				"pkg":   x.pkg,
+			},
+		})
+	}
+	for _, x := range elements.nodes {
+		d := map[string]interface{}{
+			"id":      x.id,
+			"label":   x.label,
+			"pkg":     x.pkg,
+			"name":    x.name,
+			"ref":     x.ref,
+			"def":     x.def,
+			"uniqref": x.uniqRef,
+			"uniqdef": x.uniqDef,
+		}
+		if x.parent != "" {
+			d["parent"] = x.parent
+		}
+		if x.typ != "" {
+			d["type"] = x.typ
+		}
+		if x.recv != "" {
+			d["recv"] = x.recv
+		}
+		for k, v := range x.metrics {
+			d[k] = v
+		}
+		nodes = append(nodes, &cytoscapeElement{
+			Data: d,
+		})
+	}
+	for _, x := range elements.edges {
+		edges = append(edges, &cytoscapeElement{
+			Data: map[string]interface{}{
+				"id":     x.id,
+				"source": x.source,
+				"target": x.target,
+				"weight": x.weight,
+			},
+		})
+	}
+	b, err := json.Marshal(map[string]interface{}{
+		"elements": map[string]interface{}{
+			"nodes": nodes,
+			"edges": edges,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.w, string(b))
+	return err
+}
@@ -0,0 +1,175 @@
+package display
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/logger"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/berquerant/gotypegraph/util"
+)
+
+type (
+	DeadcodeWriterConfig struct {
+		countTest     bool
+		rootMain      bool
+		rootPkgRegexp *regexp.Regexp
+		pkgNameRegexp util.RegexpPair
+		objNameRegexp util.RegexpPair
+	}
+
+	DeadcodeWriterOption func(*DeadcodeWriterConfig)
+)
+
+// WithDeadcodeWriterCountTest counts the uses from test files.
+func WithDeadcodeWriterCountTest(v bool) DeadcodeWriterOption {
+	return func(c *DeadcodeWriterConfig) {
+		c.countTest = v
+	}
+}
+
+// WithDeadcodeWriterRootMain treats main packages as roots.
+func WithDeadcodeWriterRootMain(v bool) DeadcodeWriterOption {
+	return func(c *DeadcodeWriterConfig) {
+		c.rootMain = v
+	}
+}
+
+// WithDeadcodeWriterRootPkgRegexp treats packages whose path matches v as roots.
+func WithDeadcodeWriterRootPkgRegexp(v *regexp.Regexp) DeadcodeWriterOption {
+	return func(c *DeadcodeWriterConfig) {
+		c.rootPkgRegexp = v
+	}
+}
+
+// WithDeadcodeWriterPkgNameRegexp reports only the definitions in the packages whose name matches v.
+func WithDeadcodeWriterPkgNameRegexp(v util.RegexpPair) DeadcodeWriterOption {
+	return func(c *DeadcodeWriterConfig) {
+		c.pkgNameRegexp = v
+	}
+}
+
+// WithDeadcodeWriterObjNameRegexp reports only the definitions whose name matches v.
+func WithDeadcodeWriterObjNameRegexp(v util.RegexpPair) DeadcodeWriterOption {
+	return func(c *DeadcodeWriterConfig) {
+		c.objNameRegexp = v
+	}
+}
+
+// NewDeadcodeWriter returns a writer that reports the exported definitions in defs
+// which are never referred from the other packages.
+// Definitions in the root packages are never reported.
+func NewDeadcodeWriter(w io.Writer, defs []search.DefNode, opt ...DeadcodeWriterOption) Writer {
+	var config DeadcodeWriterConfig
+	for _, x := range opt {
+		x(&config)
+	}
+	return &deadcodeWriter{
+		w:    w,
+		defs: defs,
+		used: map[string]bool{},
+		conf: &config,
+	}
+}
+
+type deadcodeWriter struct {
+	w    io.Writer
+	defs []search.DefNode
+	used map[string]bool // node id => used
+	conf *DeadcodeWriterConfig
+}
+
+func (s *deadcodeWriter) Write(node search.Use) error {
+	var (
+		ref = node.Ref()
+		def = node.Def()
+	)
+	if ref.Pkg().Path() == def.Pkg().Path() {
+		return nil
+	}
+	if !s.conf.countTest && isTestFile(s.position(ref, ref.Ident().Pos())) {
+		return nil
+	}
+	s.used[stat.NewNode(def).ID()] = true
+	return nil
+}
+
+func (s *deadcodeWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("DeadcodeWriter: %w", err)
+	}
+	return nil
+}
+
+type deadcodeEntry struct {
+	node     search.DefNode
+	position token.Position
+}
+
+func (s *deadcodeWriter) flush() error {
+	entries := []*deadcodeEntry{}
+	for _, def := range s.defs {
+		if !s.isTarget(def) || s.used[stat.NewNode(def).ID()] {
+			continue
+		}
+		entries = append(entries, &deadcodeEntry{
+			node:     def,
+			position: s.position(def, def.Obj().Pos()),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		l, r := entries[i].position, entries[j].position
+		if l.Filename != r.Filename {
+			return l.Filename < r.Filename
+		}
+		return l.Offset < r.Offset
+	})
+	logger.Infof("[DeadcodeWriter] %d unused in %d definitions", len(entries), len(s.defs))
+	for _, x := range entries {
+		if _, err := fmt.Fprintf(s.w, "%s\t%s\t%s.%s\n",
+			x.position, x.node.Type(), x.node.Pkg().Path(), x.node.Name(),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *deadcodeWriter) isTarget(def search.DefNode) bool {
+	if !def.Obj().Exported() || s.isRoot(def.Pkg()) {
+		return false
+	}
+	if s.conf.pkgNameRegexp != nil && !s.conf.pkgNameRegexp.MatchString(def.Pkg().Name()) {
+		return false
+	}
+	if s.conf.objNameRegexp != nil && !s.conf.objNameRegexp.MatchString(def.Name()) {
+		return false
+	}
+	switch def.Type() {
+	case search.FuncNodeType, search.TypeNodeType, search.VarNodeType, search.ConstNodeType:
+		return !isTestFile(s.position(def, def.Obj().Pos()))
+	default:
+		return false
+	}
+}
+
+func (s *deadcodeWriter) isRoot(pkg search.Pkg) bool {
+	if s.conf.rootMain && pkg.Name() == "main" {
+		return true
+	}
+	return s.conf.rootPkgRegexp != nil && s.conf.rootPkgRegexp.MatchString(pkg.Path())
+}
+
+func (*deadcodeWriter) position(node search.Node, pos token.Pos) token.Position {
+	if pkg := node.Pkg().Pkg(); pkg != nil && pkg.Fset != nil {
+		return pkg.Fset.Position(pos)
+	}
+	return token.Position{}
+}
+
+func isTestFile(pos token.Position) bool { return strings.HasSuffix(pos.Filename, "_test.go") }
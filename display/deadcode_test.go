@@ -0,0 +1,141 @@
+package display_test
+
+import (
+	"bytes"
+	"go/token"
+	"regexp"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadcodeWriter(t *testing.T) {
+	var (
+		fset = token.NewFileSet()
+		a    = newTestPkg(fset, "example.com/a")
+		b    = newTestPkg(fset, "example.com/b")
+		m    = newTestPkg(fset, "example.com/main")
+
+		used     = testObj{pkg: a, obj: a.newFunc("Used", "a.go", 1)}
+		unused   = testObj{pkg: a, obj: a.newFunc("Unused", "a.go", 2)}
+		private  = testObj{pkg: a, obj: a.newFunc("private", "a.go", 3)}
+		tested   = testObj{pkg: a, obj: a.newFunc("Tested", "a.go", 4)}
+		internal = testObj{pkg: a, obj: a.newFunc("Internal", "a.go", 5)}
+		kind     = testObj{pkg: a, obj: a.newType("Kind", "a.go", 6)}
+		testOnly = testObj{pkg: a, obj: a.newFunc("TestOnly", "a_test.go", 1)}
+		caller   = testObj{pkg: b, obj: b.newFunc("Caller", "b.go", 1)}
+		testB    = testObj{pkg: b, obj: b.newFunc("TestB", "b_test.go", 1)}
+		run      = testObj{pkg: m, obj: m.newFunc("Run", "main.go", 1)}
+
+		uses = []search.Use{
+			newTestUse(caller, b.pos("b.go", 2), used),
+			newTestUse(testB, b.pos("b_test.go", 2), tested),
+			newTestUse(used, a.pos("a.go", 7), internal),
+			newTestUse(used, a.pos("a.go", 8), private),
+			newTestUse(run, m.pos("main.go", 2), caller),
+		}
+		defs []search.DefNode
+	)
+	for _, x := range []testObj{used, unused, private, tested, internal, kind, testOnly, caller, testB, run} {
+		defs = append(defs, search.NewDefNode(x.pkg.pkg, x.obj, &search.NodeInfo{ValueSpecIndex: -1}))
+	}
+
+	for _, tc := range []struct {
+		title string
+		uses  []search.Use
+		opt   []display.DeadcodeWriterOption
+		want  string
+	}{
+		{
+			title: "main is root",
+			uses:  uses,
+			opt: []display.DeadcodeWriterOption{
+				display.WithDeadcodeWriterRootMain(true),
+			},
+			want: `a.go:2:1	func	example.com/a.Unused
+a.go:4:1	func	example.com/a.Tested
+a.go:5:1	func	example.com/a.Internal
+a.go:6:1	type	example.com/a.Kind
+`,
+		},
+		{
+			title: "count test",
+			uses:  uses,
+			opt: []display.DeadcodeWriterOption{
+				display.WithDeadcodeWriterRootMain(true),
+				display.WithDeadcodeWriterCountTest(true),
+			},
+			want: `a.go:2:1	func	example.com/a.Unused
+a.go:5:1	func	example.com/a.Internal
+a.go:6:1	type	example.com/a.Kind
+`,
+		},
+		{
+			title: "no roots",
+			uses:  uses,
+			want: `a.go:2:1	func	example.com/a.Unused
+a.go:4:1	func	example.com/a.Tested
+a.go:5:1	func	example.com/a.Internal
+a.go:6:1	type	example.com/a.Kind
+main.go:1:1	func	example.com/main.Run
+`,
+		},
+		{
+			title: "root regexp",
+			uses:  uses[:2],
+			opt: []display.DeadcodeWriterOption{
+				display.WithDeadcodeWriterRootMain(true),
+				display.WithDeadcodeWriterRootPkgRegexp(regexp.MustCompile(`/[ab]$`)),
+			},
+			want: "",
+		},
+		{
+			title: "deny name",
+			uses:  uses[1:],
+			opt: []display.DeadcodeWriterOption{
+				display.WithDeadcodeWriterRootMain(true),
+				display.WithDeadcodeWriterObjNameRegexp(util.NewRegexpPair(nil, regexp.MustCompile(`^(Unused|Caller)$`))),
+			},
+			want: `a.go:1:1	func	example.com/a.Used
+a.go:4:1	func	example.com/a.Tested
+a.go:5:1	func	example.com/a.Internal
+a.go:6:1	type	example.com/a.Kind
+`,
+		},
+		{
+			title: "accept pkg",
+			uses:  uses,
+			opt: []display.DeadcodeWriterOption{
+				display.WithDeadcodeWriterPkgNameRegexp(util.NewRegexpPair(regexp.MustCompile(`^(b|main)$`), nil)),
+			},
+			want: `main.go:1:1	func	example.com/main.Run
+`,
+		},
+		{
+			title: "unused caller",
+			uses:  uses[:4],
+			opt: []display.DeadcodeWriterOption{
+				display.WithDeadcodeWriterRootMain(true),
+				display.WithDeadcodeWriterRootPkgRegexp(regexp.MustCompile(`/a$`)),
+			},
+			want: `b.go:1:1	func	example.com/b.Caller
+`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			var (
+				buf bytes.Buffer
+				w   = display.NewDeadcodeWriter(&buf, defs, tc.opt...)
+			)
+			for _, x := range tc.uses {
+				assert.Nil(t, w.Write(x))
+			}
+			assert.Nil(t, w.Flush())
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/berquerant/gotypegraph/dot"
+	"github.com/berquerant/gotypegraph/snapshot"
+	"github.com/berquerant/gotypegraph/util"
+)
+
+// DiffWriter writes the difference of the dependencies.
+type DiffWriter interface {
+	Write(*snapshot.Diff) error
+}
+
+// NewTextDiffWriter returns a writer that writes the added edges with "+",
+// the removed edges with "-" and the changed edges with "~".
+func NewTextDiffWriter(w io.Writer) DiffWriter {
+	return &textDiffWriter{
+		w: w,
+	}
+}
+
+type textDiffWriter struct {
+	w io.Writer
+}
+
+func (s *textDiffWriter) Write(diff *snapshot.Diff) error {
+	var b util.StringBuilder
+	b.Writeln("# packages")
+	s.writeEdgeSetDiff(&b, diff.Pkgs)
+	b.Writeln("\n# nodes")
+	s.writeEdgeSetDiff(&b, diff.Nodes)
+	if _, err := fmt.Fprint(s.w, b.String()); err != nil {
+		return fmt.Errorf("TextDiffWriter: %w", err)
+	}
+	return nil
+}
+
+func (*textDiffWriter) writeEdgeSetDiff(b *util.StringBuilder, diff *snapshot.EdgeSetDiff) {
+	for _, x := range diff.Added {
+		b.Writelnf("+ %s -> %s [%d]", x.Ref, x.Def, x.Weight)
+	}
+	for _, x := range diff.Removed {
+		b.Writelnf("- %s -> %s [%d]", x.Ref, x.Def, x.Weight)
+	}
+	for _, x := range diff.Changed {
+		b.Writelnf("~ %s -> %s [%d -> %d]", x.Ref, x.Def, x.Before, x.After)
+	}
+}
+
+func NewJSONDiffWriter(w io.Writer) DiffWriter {
+	return &jsonDiffWriter{
+		w: w,
+	}
+}
+
+type jsonDiffWriter struct {
+	w io.Writer
+}
+
+func (s *jsonDiffWriter) Write(diff *snapshot.Diff) error {
+	b, err := json.Marshal(diff)
+	if err != nil {
+		return fmt.Errorf("JSONDiffWriter: %w", err)
+	}
+	if _, err := fmt.Fprintln(s.w, string(b)); err != nil {
+		return fmt.Errorf("JSONDiffWriter: %w", err)
+	}
+	return nil
+}
+
+// NewDotDiffWriter returns a writer that draws the added edges in green,
+// the removed edges in red and the changed edges in blue.
+// Draws the package dependencies if usePkg is true, else the node dependencies.
+func NewDotDiffWriter(w io.Writer, usePkg bool) DiffWriter {
+	return &dotDiffWriter{
+		w:      w,
+		usePkg: usePkg,
+	}
+}
+
+type dotDiffWriter struct {
+	w      io.Writer
+	usePkg bool
+}
+
+func (s *dotDiffWriter) Write(diff *snapshot.Diff) error {
+	edgeSetDiff := diff.Nodes
+	if s.usePkg {
+		edgeSetDiff = diff.Pkgs
+	}
+	if _, err := fmt.Fprintln(s.w, s.build(edgeSetDiff).String()); err != nil {
+		return fmt.Errorf("DotDiffWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *dotDiffWriter) build(diff *snapshot.EdgeSetDiff) dot.Graph {
+	var (
+		nodeList = dot.NewNodeList()
+		edgeList = dot.NewEdgeList()
+		nodeSet  = util.NewStringSet()
+	)
+	addNode := func(id string) {
+		if nodeSet.In(id) {
+			return
+		}
+		nodeSet.Add(id)
+		nodeList.Add(dot.NewNode(dot.ID(id), dot.WithNodeAttrList(dot.NewAttrList().
+			Add(dot.NewAttr("shape", "box")).
+			Add(dot.NewAttr("label", id)))))
+	}
+	addEdge := func(ref, def, color, style, label string) {
+		addNode(ref)
+		addNode(def)
+		tooltip := fmt.Sprintf("%s -> %s [%s]", ref, def, label)
+		edgeList.Add(dot.NewEdge(dot.ID(ref), dot.ID(def), dot.WithEdgeAttrList(dot.NewAttrList().
+			Add(dot.NewAttr("color", color)).
+			Add(dot.NewAttr("fontcolor", color)).
+			Add(dot.NewAttr("style", style)).
+			Add(dot.NewAttr("label", label)).
+			Add(dot.NewAttr("tooltip", tooltip)).
+			Add(dot.NewAttr("labeltooltip", tooltip)))))
+	}
+	for _, x := range diff.Added {
+		addEdge(x.Ref, x.Def, "green", "solid", fmt.Sprint(x.Weight))
+	}
+	for _, x := range diff.Removed {
+		addEdge(x.Ref, x.Def, "red", "dashed", fmt.Sprint(x.Weight))
+	}
+	for _, x := range diff.Changed {
+		addEdge(x.Ref, x.Def, "blue", "solid", fmt.Sprintf("%d -> %d", x.Before, x.After))
+	}
+	return dot.NewGraph("G", nodeList, edgeList)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/berquerant/gotypegraph/display/jsonify"
+	"github.com/berquerant/gotypegraph/graph"
 	"github.com/berquerant/gotypegraph/search"
 )
 
@@ -22,6 +23,11 @@ type (
 		maxPenwidth int
 		minWeight   int
 		maxWeight   int
+		metric      graph.Metric
+		layer       bool
+		cluster     bool
+		closure     bool
+		foldType    bool
 	}
 
 	WriterOption func(*WriterConfig)
@@ -85,6 +91,45 @@ func WithWriterMaxWeight(v int) WriterOption {
 	}
 }
 
+// WithWriterMetric makes the fontsize of the nodes and the penwidth of the edges
+// be determined by the metric of the nodes instead of the weights.
+// The penwidth of an edge is determined by the metric of the head.
+func WithWriterMetric(v graph.Metric) WriterOption {
+	return func(c *WriterConfig) {
+		c.metric = v
+	}
+}
+
+// WithWriterLayer draws the packages in the same layer on the same rank.
+func WithWriterLayer(v bool) WriterOption {
+	return func(c *WriterConfig) {
+		c.layer = v
+	}
+}
+
+// WithWriterCluster draws the clusters of the nodes detected by stat.NewNodeClusters
+// as the subgraphs in the package subgraphs.
+func WithWriterCluster(v bool) WriterOption {
+	return func(c *WriterConfig) {
+		c.cluster = v
+	}
+}
+
+// WithWriterClosure adds the sizes of the transitive closures to the node labels.
+func WithWriterClosure(v bool) WriterOption {
+	return func(c *WriterConfig) {
+		c.closure = v
+	}
+}
+
+// WithWriterFoldType merges the methods and the fields into the nodes of their receiver types
+// and draws the types as records of the fields and the methods.
+func WithWriterFoldType(v bool) WriterOption {
+	return func(c *WriterConfig) {
+		c.foldType = v
+	}
+}
+
 func NewJSONWriter(w io.Writer) Writer {
 	return &jsonWriter{
 		w: w,
@@ -113,3 +158,26 @@ func (s *jsonWriter) write(node search.Use) error {
 }
 
 func (*jsonWriter) Flush() error { return nil }
+
+// sequentialIDs assigns the ids consisting of the prefix and the sequential number to the keys,
+// the ids are safe in the graph languages whatever the keys contain.
+type sequentialIDs struct {
+	prefix string
+	d      map[string]string
+}
+
+func newSequentialIDs(prefix string) *sequentialIDs {
+	return &sequentialIDs{
+		prefix: prefix,
+		d:      map[string]string{},
+	}
+}
+
+func (s *sequentialIDs) get(key string) string {
+	if x, ok := s.d[key]; ok {
+		return x
+	}
+	x := fmt.Sprintf("%s%d", s.prefix, len(s.d))
+	s.d[key] = x
+	return x
+}
@@ -0,0 +1,77 @@
+package display_test
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+
+	"github.com/berquerant/gotypegraph/search"
+	"golang.org/x/tools/go/packages"
+)
+
+// testPkg builds the objects of a package by hand.
+// Each line of the files is 10 bytes wide.
+type testPkg struct {
+	pkg   search.Pkg
+	types *types.Package
+	fset  *token.FileSet
+	files map[string]*token.File
+}
+
+func newTestPkg(fset *token.FileSet, pkgPath string) *testPkg {
+	var (
+		name = path.Base(pkgPath)
+		tpkg = types.NewPackage(pkgPath, name)
+	)
+	return &testPkg{
+		pkg: search.NewPkg(&packages.Package{
+			ID:      pkgPath,
+			Name:    name,
+			PkgPath: pkgPath,
+			Fset:    fset,
+			Types:   tpkg,
+		}),
+		types: tpkg,
+		fset:  fset,
+		files: map[string]*token.File{},
+	}
+}
+
+func (s *testPkg) pos(filename string, line int) token.Pos {
+	f, ok := s.files[filename]
+	if !ok {
+		lines := make([]int, 100)
+		for i := range lines {
+			lines[i] = i * 10
+		}
+		f = s.fset.AddFile(filename, -1, len(lines)*10)
+		f.SetLines(lines)
+		s.files[filename] = f
+	}
+	return f.LineStart(line)
+}
+
+func (s *testPkg) newFunc(name, filename string, line int) types.Object {
+	return types.NewFunc(s.pos(filename, line), s.types, name, types.NewSignature(nil, nil, nil, false))
+}
+
+func (s *testPkg) newType(name, filename string, line int) types.Object {
+	return types.NewTypeName(s.pos(filename, line), s.types, name, types.Typ[types.Int])
+}
+
+// testObj is an object in the package.
+type testObj struct {
+	pkg *testPkg
+	obj types.Object
+}
+
+// newTestUse returns the use of def by ref at the position.
+func newTestUse(ref testObj, pos token.Pos, def testObj) search.Use {
+	ident := ast.NewIdent(def.obj.Name())
+	ident.NamePos = pos
+	return search.NewUse(
+		search.NewRefNode(ref.pkg.pkg, ref.obj, &search.NodeInfo{ValueSpecIndex: -1}, nil, ident),
+		search.NewDefNode(def.pkg.pkg, def.obj, &search.NodeInfo{ValueSpecIndex: -1}),
+	)
+}
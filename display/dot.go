@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/berquerant/gotypegraph/graph"
 	"github.com/berquerant/gotypegraph/util"
 )
 
@@ -35,36 +36,56 @@ func (s *WriterConfig) newWeightRanking(r util.Ranking) *attrRanking {
 	}
 }
 
+// metricValues returns the ordinals of the metric scores by node id.
+// Returns nil if no metric is chosen.
+func (s *WriterConfig) metricValues(g graph.Graph) map[string]int {
+	if s.metric == graph.UnknownMetric {
+		return nil
+	}
+	return s.metric.Calculate(g).Ordinal()
+}
+
+// nodeLabelRow is an additional row of the node label.
+type nodeLabelRow struct {
+	key   string
+	value int
+}
+
 func generateNodeLabelHTML(
 	titleKey, titleValue string,
 	ref, def, uniqRef, uniqDef int,
+	rows ...*nodeLabelRow,
 ) string {
-	return fmt.Sprintf(`<table border="0">
+	var b util.StringBuilder
+	b.Writef(`<table border="0">
   <tr>
     <td><b>%s</b></td>
     <td><b>%s</b></td>
-  </tr>
-  <tr>
-    <td align="left"><b>RefDef</b></td>
-    <td align="right">%d</td>
-  </tr>
-  <tr>
-    <td align="left"><b>Ref</b></td>
-    <td align="right">%d</td>
-  </tr>
+  </tr>`, titleKey, titleValue)
+	for _, row := range append([]*nodeLabelRow{
+		{key: "RefDef", value: ref + def},
+		{key: "Ref", value: ref},
+		{key: "Def", value: def},
+		{key: "UniqRef", value: uniqRef},
+		{key: "UniqDef", value: uniqDef},
+	}, rows...) {
+		b.Writef(`
   <tr>
-    <td align="left"><b>Def</b></td>
+    <td align="left"><b>%s</b></td>
     <td align="right">%d</td>
-  </tr>
-  <tr>
-    <td align="left"><b>UniqRef</b></td>
-    <td align="right">%d</td>
-  </tr>
-  <tr>
-    <td align="left"><b>UniqDef</b></td>
-    <td align="right">%d</td>
-  </tr>
-</table>`, titleKey, titleValue, ref+def, ref, def, uniqRef, uniqDef)
+  </tr>`, row.key, row.value)
+	}
+	b.Write("\n</table>")
+	return b.String()
+}
+
+// closureLabelRows returns the rows of the sizes of the transitive closures.
+// TransRef is the number of the transitive dependencies, TransDef is the number of the transitive dependents.
+func closureLabelRows(dependencies, dependents int) []*nodeLabelRow {
+	return []*nodeLabelRow{
+		{key: "TransRef", value: dependencies},
+		{key: "TransDef", value: dependents},
+	}
 }
 
 type (
@@ -0,0 +1,300 @@
+package display
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/berquerant/gotypegraph/search"
+)
+
+// DSMFormat is the output format of the dependency structure matrix.
+type DSMFormat int
+
+const (
+	DSMText DSMFormat = iota
+	DSMCSV
+	DSMHTML
+)
+
+// NewDSMFormat returns the format by name, text, csv or html.
+func NewDSMFormat(name string) (DSMFormat, bool) {
+	switch name {
+	case "text":
+		return DSMText, true
+	case "csv":
+		return DSMCSV, true
+	case "html":
+		return DSMHTML, true
+	default:
+		return DSMText, false
+	}
+}
+
+// NewNodeDSMWriter returns a writer that writes the dependency structure matrix of the definitions.
+func NewNodeDSMWriter(w io.Writer, format DSMFormat) Writer {
+	return &dsmWriter{
+		w:         w,
+		format:    format,
+		calc:      newNodeElementsCalculator(false),
+		withScope: true,
+	}
+}
+
+// NewPackageDSMWriter returns a writer that writes the dependency structure matrix of the packages.
+func NewPackageDSMWriter(w io.Writer, format DSMFormat) Writer {
+	return &dsmWriter{
+		w:      w,
+		format: format,
+		calc:   newPackageElementsCalculator(false),
+	}
+}
+
+type dsmWriter struct {
+	w      io.Writer
+	format DSMFormat
+	calc   graphElementsCalculator
+	// withScope prefixes the labels with the package names.
+	withScope bool
+}
+
+func (s *dsmWriter) Write(node search.Use) error {
+	s.calc.Add(node)
+	return nil
+}
+
+func (s *dsmWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("DSMWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *dsmWriter) flush() error {
+	m := newDSM(s.calc.Result(), s.withScope)
+	switch s.format {
+	case DSMCSV:
+		return m.writeCSV(s.w)
+	case DSMHTML:
+		return m.writeHTML(s.w)
+	default:
+		return m.writeText(s.w)
+	}
+}
+
+// dsm is a dependency structure matrix.
+// The rows are in topological order, the referred nodes first, and the nodes in the same cycle are adjacent,
+// so the cell (i, j) is the weight of the dependency of the row i on the column j
+// and the cells above the diagonal are the dependencies in the cycles.
+type dsm struct {
+	labels []string
+	// cycles are the cycle numbers of the rows, 0 if the row is not in a cycle.
+	cycles []int
+	cells  map[[2]int]int
+}
+
+func newDSM(elements *graphElements, withScope bool) *dsm {
+	var (
+		g      = graph.New()
+		labels = map[string]string{}
+		scopes = map[string]string{}
+	)
+	for _, x := range elements.parents {
+		scopes[x.id] = x.label
+	}
+	for _, x := range elements.nodes {
+		g.AddNode(x.id)
+		labels[x.id] = x.label
+		if scope, ok := scopes[x.parent]; withScope && ok {
+			labels[x.id] = scope + "." + x.label
+		}
+	}
+	for _, x := range elements.edges {
+		g.AddEdge(x.source, x.target, x.weight)
+	}
+
+	var (
+		m     = &dsm{cells: map[[2]int]int{}}
+		index = map[string]int{}
+		cycle int
+	)
+	for _, c := range graph.NewLayering(g).Components() {
+		var n int
+		if len(c) > 1 {
+			cycle++
+			n = cycle
+		}
+		for _, x := range c {
+			index[x] = len(m.labels)
+			m.labels = append(m.labels, labels[x])
+			m.cycles = append(m.cycles, n)
+		}
+	}
+	for _, e := range g.Edges() {
+		m.cells[[2]int{index[e.From()], index[e.To()]}] += e.Weight()
+	}
+	return m
+}
+
+func (s *dsm) cell(i, j int) (int, bool) {
+	x, ok := s.cells[[2]int{i, j}]
+	return x, ok
+}
+
+func (s *dsm) cycleString(i int) string {
+	if s.cycles[i] == 0 {
+		return ""
+	}
+	return fmt.Sprintf("c%d", s.cycles[i])
+}
+
+// writeText writes the matrix, the cells above the diagonal are marked with *.
+func (s *dsm) writeText(w io.Writer) error {
+	var (
+		b          = bufio.NewWriter(w)
+		n          = len(s.labels)
+		numWidth   = len(strconv.Itoa(n))
+		labelWidth int
+		cellWidth  = numWidth
+	)
+	for _, x := range s.labels {
+		if len(x) > labelWidth {
+			labelWidth = len(x)
+		}
+	}
+	for _, x := range s.cells {
+		if l := len(strconv.Itoa(x)) + 1; l > cellWidth {
+			cellWidth = l
+		}
+	}
+	var cycleWidth int
+	for i := range s.cycles {
+		if l := len(s.cycleString(i)); l > cycleWidth {
+			cycleWidth = l
+		}
+	}
+
+	fmt.Fprintf(b, "%*s %*s %-*s |", numWidth, "", cycleWidth, "", labelWidth, "")
+	for j := 0; j < n; j++ {
+		fmt.Fprintf(b, " %*d", cellWidth, j+1)
+	}
+	fmt.Fprintln(b)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(b, "%*d %*s %-*s |", numWidth, i+1, cycleWidth, s.cycleString(i), labelWidth, s.labels[i])
+		for j := 0; j < n; j++ {
+			var v string
+			x, ok := s.cell(i, j)
+			switch {
+			case ok && j > i:
+				v = strconv.Itoa(x) + "*"
+			case ok:
+				v = strconv.Itoa(x)
+			case i == j:
+				v = "-"
+			default:
+				v = "."
+			}
+			fmt.Fprintf(b, " %*s", cellWidth, v)
+		}
+		fmt.Fprintln(b)
+	}
+	return b.Flush()
+}
+
+// writeCSV writes the matrix with the cycle numbers.
+func (s *dsm) writeCSV(w io.Writer) error {
+	var (
+		c      = csv.NewWriter(w)
+		header = []string{"", "cycle"}
+	)
+	header = append(header, s.labels...)
+	_ = c.Write(header)
+	for i, label := range s.labels {
+		row := []string{label, s.cycleString(i)}
+		for j := range s.labels {
+			var v string
+			if x, ok := s.cell(i, j); ok {
+				v = strconv.Itoa(x)
+			}
+			row = append(row, v)
+		}
+		_ = c.Write(row)
+	}
+	c.Flush()
+	return c.Error()
+}
+
+const dsmHTMLHead = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>gotypegraph DSM</title>
+<style>
+  body { font-family: sans-serif; font-size: 11px; }
+  table { border-collapse: collapse; }
+  th, td { border: 1px solid #ddd; padding: 1px 3px; text-align: center; min-width: 14px; }
+  th.label { text-align: left; white-space: nowrap; position: sticky; left: 0; background: #fff; }
+  thead th { position: sticky; top: 0; background: #fff; }
+  td.diag { background: #ccc; }
+  td.dep { background: #cfe3ff; }
+  td.cycle { background: #f88; font-weight: bold; }
+  td.block { background: #fff3d6; }
+</style>
+</head>
+<body>
+<p>Rows depend on columns. Red cells above the diagonal are dependencies in cycles, the shaded blocks are the cycles.</p>
+<table>
+`
+
+const dsmHTMLTail = `</table>
+</body>
+</html>
+`
+
+// writeHTML writes the matrix as a table, the cells above the diagonal are highlighted.
+func (s *dsm) writeHTML(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	b.WriteString(dsmHTMLHead)
+	b.WriteString("<thead><tr><th></th><th>cycle</th><th class=\"label\"></th>")
+	for j, label := range s.labels {
+		fmt.Fprintf(b, "<th title=\"%s\">%d</th>", html.EscapeString(label), j+1)
+	}
+	b.WriteString("</tr></thead>\n<tbody>\n")
+	for i, label := range s.labels {
+		fmt.Fprintf(b, "<tr><th>%d</th><td>%s</td><th class=\"label\">%s</th>", i+1, s.cycleString(i), html.EscapeString(label))
+		for j := range s.labels {
+			var class string
+			x, ok := s.cell(i, j)
+			switch {
+			case i == j:
+				class = "diag"
+			case ok && j > i:
+				class = "cycle"
+			case ok:
+				class = "dep"
+			case s.cycles[i] != 0 && s.cycles[i] == s.cycles[j]:
+				class = "block"
+			}
+			if !ok {
+				// empty cells are plain to keep the large matrices small
+				if class == "" {
+					b.WriteString("<td></td>")
+				} else {
+					fmt.Fprintf(b, "<td class=\"%s\"></td>", class)
+				}
+				continue
+			}
+			fmt.Fprintf(b, "<td class=\"%s\" title=\"%s -> %s\">%d</td>",
+				class, html.EscapeString(label), html.EscapeString(s.labels[j]), x,
+			)
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n")
+	b.WriteString(dsmHTMLTail)
+	return b.Flush()
+}
@@ -0,0 +1,108 @@
+package display_test
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDSMWriter(t *testing.T) {
+	var (
+		fset = token.NewFileSet()
+		a    = newTestPkg(fset, "a")
+		b    = newTestPkg(fset, "b")
+		c    = newTestPkg(fset, "c")
+		d    = newTestPkg(fset, "d")
+
+		fa = testObj{pkg: a, obj: a.newFunc("FA", "a.go", 1)}
+		ga = testObj{pkg: a, obj: a.newFunc("GA", "a.go", 2)}
+		fb = testObj{pkg: b, obj: b.newFunc("FB", "b.go", 1)}
+		fc = testObj{pkg: c, obj: c.newFunc("FC", "c.go", 1)}
+		fd = testObj{pkg: d, obj: d.newFunc("FD", "d.go", 1)}
+
+		// d -> a -> b <-> c, a -> a
+		uses = []search.Use{
+			newTestUse(fd, d.pos("d.go", 2), fa),
+			newTestUse(fa, a.pos("a.go", 3), fb),
+			newTestUse(fa, a.pos("a.go", 4), fb),
+			newTestUse(fa, a.pos("a.go", 5), ga),
+			newTestUse(fb, b.pos("b.go", 2), fc),
+			newTestUse(fc, c.pos("c.go", 2), fb),
+		}
+	)
+
+	for _, tc := range []struct {
+		title  string
+		format display.DSMFormat
+		want   string
+	}{
+		{
+			title:  "text",
+			format: display.DSMText,
+			want: `       |  1  2  3  4
+1 c1 b |  - 1*  .  .
+2 c1 c |  1  -  .  .
+3    a |  2  .  1  .
+4    d |  .  .  1  -
+`,
+		},
+		{
+			title:  "csv",
+			format: display.DSMCSV,
+			want: `,cycle,b,c,a,d
+b,c1,,1,,
+c,c1,1,,,
+a,,2,,1,
+d,,,,1,
+`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			var (
+				buf bytes.Buffer
+				w   = display.NewPackageDSMWriter(&buf, tc.format)
+			)
+			for _, x := range uses {
+				assert.Nil(t, w.Write(x))
+			}
+			assert.Nil(t, w.Flush())
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+
+	t.Run("html", func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+			w   = display.NewPackageDSMWriter(&buf, display.DSMHTML)
+		)
+		for _, x := range uses {
+			assert.Nil(t, w.Write(x))
+		}
+		assert.Nil(t, w.Flush())
+		got := buf.String()
+		assert.Contains(t, got, `<td class="cycle" title="b -> c">1</td>`)
+		assert.Contains(t, got, `<td class="dep" title="c -> b">1</td>`)
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`class="cycle"`)))
+	})
+	t.Run("node", func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+			w   = display.NewNodeDSMWriter(&buf, display.DSMCSV)
+		)
+		for _, x := range uses[:4] {
+			assert.Nil(t, w.Write(x))
+		}
+		assert.Nil(t, w.Flush())
+		assert.Equal(t, `,cycle,a.GA,b.FB,a.FA,d.FD
+a.GA,,,,,
+b.FB,,,,,
+a.FA,,1,2,,
+d.FD,,,,1,
+`, buf.String())
+	})
+}
@@ -0,0 +1,247 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+type (
+	// graphElements is a graph for the generic graph formats.
+	graphElements struct {
+		// parents are the compound nodes, the packages of the nodes.
+		parents []*graphElementNode
+		nodes   []*graphElementNode
+		edges   []*graphElementEdge
+	}
+
+	graphElementNode struct {
+		id     string
+		label  string
+		parent string
+		pkg    string
+		name   string
+		typ    string
+		recv   string
+		// position is the source position of the definition.
+		position string
+		ref      int
+		def      int
+		uniqRef  int
+		uniqDef  int
+		// metrics are the scores by metric name.
+		metrics map[string]float64
+	}
+
+	graphElementEdge struct {
+		id     string
+		source string
+		target string
+		weight int
+	}
+
+	graphElementsCalculator interface {
+		Add(search.Use)
+		Result() *graphElements
+	}
+)
+
+// graphElementMetricScores calculates all the metrics of the graph.
+func graphElementMetricScores(g graph.Graph) map[string]graph.Scores {
+	d := map[string]graph.Scores{}
+	for _, m := range graph.Metrics() {
+		d[m.String()] = m.Calculate(g)
+	}
+	return d
+}
+
+// graphElementMetrics returns the scores of the node, nil if no metrics are calculated.
+func graphElementMetrics(scores map[string]graph.Scores, id string) map[string]float64 {
+	if scores == nil {
+		return nil
+	}
+	d := make(map[string]float64, len(scores))
+	for name, x := range scores {
+		d[name] = x[id]
+	}
+	return d
+}
+
+// nodePosition returns the source position of the definition, empty if unknown.
+func nodePosition(node search.Node) string {
+	if pkg := node.Pkg().Pkg(); pkg != nil && pkg.Fset != nil && node.Obj().Pos().IsValid() {
+		return pkg.Fset.Position(node.Obj().Pos()).String()
+	}
+	return ""
+}
+
+func lessRefDef(leftRef, leftDef, rightRef, rightDef string) bool {
+	if leftRef != rightRef {
+		return leftRef < rightRef
+	}
+	return leftDef < rightDef
+}
+
+// sortNodeDeps sorts the dependencies by the references and the definitions.
+func sortNodeDeps(deps []stat.NodeDep) {
+	sort.Slice(deps, func(i, j int) bool {
+		return lessRefDef(deps[i].Ref().ID(), deps[i].Def().ID(), deps[j].Ref().ID(), deps[j].Def().ID())
+	})
+}
+
+// sortPkgDeps sorts the dependencies by the references and the definitions.
+func sortPkgDeps(deps []stat.PkgDep) {
+	sort.Slice(deps, func(i, j int) bool {
+		return lessRefDef(deps[i].Ref().ID(), deps[i].Def().ID(), deps[j].Ref().ID(), deps[j].Def().ID())
+	})
+}
+
+// sortGroupDeps sorts the dependencies by the references and the definitions.
+func sortGroupDeps(deps []stat.GroupDep) {
+	sort.Slice(deps, func(i, j int) bool {
+		return lessRefDef(deps[i].Ref().ID(), deps[i].Def().ID(), deps[j].Ref().ID(), deps[j].Def().ID())
+	})
+}
+
+func newGraphElementEdge(i int, source, target string, weight int) *graphElementEdge {
+	return &graphElementEdge{
+		id:     fmt.Sprintf("e%d", i),
+		source: source,
+		target: target,
+		weight: weight,
+	}
+}
+
+// newNodeElementsCalculator returns the calculator of the definitions whose parents are the packages.
+// The metrics are calculated only if withMetrics because some of them are expensive.
+func newNodeElementsCalculator(withMetrics bool) graphElementsCalculator {
+	return &nodeElementsCalculator{
+		depCalc:     stat.NewNodeDepCalculator(),
+		statDepCalc: stat.NewNodeStatCalculator(),
+		withMetrics: withMetrics,
+	}
+}
+
+type nodeElementsCalculator struct {
+	depCalc     stat.NodeDepCalculator
+	statDepCalc stat.NodeStatCalculator
+	withMetrics bool
+}
+
+func (s *nodeElementsCalculator) Add(node search.Use) {
+	var (
+		ref = stat.NewNode(node.Ref())
+		def = stat.NewNode(node.Def())
+	)
+	s.depCalc.Add(ref, def)
+	s.statDepCalc.Add(ref, def)
+}
+
+func (s *nodeElementsCalculator) Result() *graphElements {
+	var (
+		deps   = s.depCalc.Result()
+		stats  = s.statDepCalc.Result().Stats()
+		scores map[string]graph.Scores
+		r      = &graphElements{}
+		pkgs   = map[string]bool{}
+	)
+	if s.withMetrics {
+		scores = graphElementMetricScores(stat.NewNodeGraph(deps))
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Node().ID() < stats[j].Node().ID() })
+	for _, st := range stats {
+		var (
+			pkg  = st.Node().Pkg()
+			node = st.Node().Node()
+		)
+		if !pkgs[pkg.ID()] {
+			pkgs[pkg.ID()] = true
+			r.parents = append(r.parents, &graphElementNode{
+				id:    pkg.ID(),
+				label: pkg.Pkg().Name(),
+				pkg:   pkg.ID(),
+				name:  pkg.Pkg().Name(),
+			})
+		}
+		r.nodes = append(r.nodes, &graphElementNode{
+			id:       st.Node().ID(),
+			label:    nodeNameWithRecv(node),
+			parent:   pkg.ID(),
+			pkg:      pkg.ID(),
+			name:     node.Name(),
+			typ:      node.Type().String(),
+			recv:     node.RecvString(),
+			position: nodePosition(node),
+			ref:      st.Refs().Weight(),
+			def:      st.Defs().Weight(),
+			uniqRef:  len(st.Refs().Deps()),
+			uniqDef:  len(st.Defs().Deps()),
+			metrics:  graphElementMetrics(scores, st.Node().ID()),
+		})
+	}
+	sort.Slice(r.parents, func(i, j int) bool { return r.parents[i].id < r.parents[j].id })
+	sortNodeDeps(deps)
+	for i, dep := range deps {
+		r.edges = append(r.edges, newGraphElementEdge(i, dep.Ref().ID(), dep.Def().ID(), dep.Weight()))
+	}
+	return r
+}
+
+// newPackageElementsCalculator returns the calculator of the packages.
+// The metrics are calculated only if withMetrics.
+func newPackageElementsCalculator(withMetrics bool) graphElementsCalculator {
+	return &packageElementsCalculator{
+		depCalc:     stat.NewPkgDepCalculator(),
+		statDepCalc: stat.NewPkgStatCalculator(),
+		withMetrics: withMetrics,
+	}
+}
+
+type packageElementsCalculator struct {
+	depCalc     stat.PkgDepCalculator
+	statDepCalc stat.PkgStatCalculator
+	withMetrics bool
+}
+
+func (s *packageElementsCalculator) Add(node search.Use) {
+	var (
+		ref = stat.NewPkg(node.Ref().Pkg())
+		def = stat.NewPkg(node.Def().Pkg())
+	)
+	s.depCalc.Add(ref, def)
+	s.statDepCalc.Add(ref, def)
+}
+
+func (s *packageElementsCalculator) Result() *graphElements {
+	var (
+		deps   = s.depCalc.Result()
+		stats  = s.statDepCalc.Result().Stats()
+		scores map[string]graph.Scores
+		r      = &graphElements{}
+	)
+	if s.withMetrics {
+		scores = graphElementMetricScores(stat.NewPkgGraph(deps))
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Pkg().ID() < stats[j].Pkg().ID() })
+	for _, st := range stats {
+		r.nodes = append(r.nodes, &graphElementNode{
+			id:      st.Pkg().ID(),
+			label:   st.Pkg().ID(),
+			pkg:     st.Pkg().ID(),
+			name:    st.Pkg().Pkg().Name(),
+			ref:     st.Refs().Weight(),
+			def:     st.Defs().Weight(),
+			uniqRef: len(st.Refs().Deps()),
+			uniqDef: len(st.Defs().Deps()),
+			metrics: graphElementMetrics(scores, st.Pkg().ID()),
+		})
+	}
+	sortPkgDeps(deps)
+	for i, dep := range deps {
+		r.edges = append(r.edges, newGraphElementEdge(i, dep.Ref().ID(), dep.Def().ID(), dep.Weight()))
+	}
+	return r
+}
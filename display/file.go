@@ -0,0 +1,245 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/berquerant/gotypegraph/dot"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/berquerant/gotypegraph/util"
+)
+
+// NewFileDotWriter returns a writer that draws the dependencies between the source files, clustered by package.
+// The references in a file are counted but not drawn.
+func NewFileDotWriter(w io.Writer, opt ...WriterOption) Writer {
+	conf := newWriterConfig()
+	for _, x := range opt {
+		x(conf)
+	}
+	return &fileDotWriter{
+		w:        w,
+		depCalc:  stat.NewFileDepCalculator(),
+		statCalc: stat.NewFileStatCalculator(),
+		conf:     conf,
+	}
+}
+
+type fileDotWriter struct {
+	w        io.Writer
+	depCalc  stat.FileDepCalculator
+	statCalc stat.FileStatCalculator
+	conf     *WriterConfig
+}
+
+func (s *fileDotWriter) Write(node search.Use) error {
+	ref, def := newFileUse(node)
+	s.depCalc.Add(ref, def)
+	s.statCalc.Add(ref, def)
+	return nil
+}
+
+// newFileUse returns the files of the use.
+// The packages share the file set so the definitions in the foreign packages are resolved by the file set of the reference.
+func newFileUse(node search.Use) (stat.File, stat.File) {
+	var fset *token.FileSet
+	if pkg := node.Ref().Pkg().Pkg(); pkg != nil {
+		fset = pkg.Fset
+	}
+	return stat.NewRefFile(node.Ref()), stat.NewDefFile(node.Def(), fset)
+}
+
+func (s *fileDotWriter) Flush() error {
+	if _, err := fmt.Fprintln(s.w, s.build().String()); err != nil {
+		return fmt.Errorf("FileDotWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *fileDotWriter) build() dot.Graph {
+	var (
+		deps  = s.interFileDeps(s.depCalc.Result())
+		stats = s.statCalc.Result()
+
+		metricValues    = s.conf.metricValues(stat.NewFileGraph(deps))
+		fontsizeRanking = s.fontsizeRanking(stats, metricValues)
+
+		pkgList      []stat.Pkg
+		pkgNodeLists = map[string]dot.NodeList{}
+		// the file paths may contain the characters unavailable in the ids
+		nodeIDs = newSequentialIDs("file_")
+	)
+
+	for _, st := range stats {
+		var (
+			fontsize = fontsizeRanking.get(s.nodeValue(st, metricValues))
+			attrList = dot.NewAttrList().
+					Add(dot.NewAttr("color", "white")).
+					Add(dot.NewAttr("style", "filled")).
+					Add(dot.NewAttr("shape", "box")).
+					Add(dot.NewAttr("label", s.nodeLabel(st), dot.WithAttrRaw(true))).
+					Add(dot.NewAttr("tooltip", st.File().ID())).
+					Add(dot.NewAttr("fontsize", strconv.Itoa(fontsize)))
+			pkg = st.File().Pkg()
+		)
+		nodeList, ok := pkgNodeLists[pkg.ID()]
+		if !ok {
+			nodeList = dot.NewNodeList()
+			pkgNodeLists[pkg.ID()] = nodeList
+			pkgList = append(pkgList, pkg)
+		}
+		nodeList.Add(dot.NewNode(dot.ID(nodeIDs.get(st.File().ID())), dot.WithNodeAttrList(attrList)))
+	}
+
+	subgraphList := dot.NewSubgraphList()
+	sort.Slice(pkgList, func(i, j int) bool { return pkgList[i].ID() < pkgList[j].ID() })
+	for _, pkg := range pkgList {
+		subgraphList.Add(dot.NewSubgraph(
+			dot.ID(pkg.ID()),
+			pkgNodeLists[pkg.ID()],
+			dot.WithSubgraphCluster(true),
+			dot.WithSubgraphAttrList(dot.NewAttrList().
+				Add(dot.NewAttr("color", "lightgrey")).
+				Add(dot.NewAttr("style", "filled")).
+				Add(dot.NewAttr("label", pkg.Pkg().Name())).
+				Add(dot.NewAttr("tooltip", pkg.Pkg().Path()))),
+		))
+	}
+
+	var (
+		edgeList = dot.NewEdgeList()
+
+		penwidthRanking = s.penwidthRanking(deps, metricValues)
+		weightRanking   = s.weightRanking(deps)
+	)
+
+	for _, dep := range deps {
+		var (
+			penwidth  = penwidthRanking.get(s.edgeValue(dep, metricValues))
+			arrowsize = float64(penwidth) / 2
+			weight    = weightRanking.get(dep.Weight())
+			tooltip   = fmt.Sprintf("%s -> %s [%d]", dep.Ref().ID(), dep.Def().ID(), dep.Weight())
+			attrList  = dot.NewAttrList().
+					Add(dot.NewAttr("label", strconv.Itoa(dep.Weight()))).
+					Add(dot.NewAttr("tooltip", tooltip)).
+					Add(dot.NewAttr("labeltooltip", tooltip)).
+					Add(dot.NewAttr("penwidth", strconv.Itoa(penwidth))).
+					Add(dot.NewAttr("arrowsize", fmt.Sprint(arrowsize))).
+					Add(dot.NewAttr("weight", strconv.Itoa(weight)))
+		)
+		edgeList.Add(dot.NewEdge(
+			dot.ID(nodeIDs.get(dep.Ref().ID())),
+			dot.ID(nodeIDs.get(dep.Def().ID())),
+			dot.WithEdgeAttrList(attrList),
+		))
+	}
+	return dot.NewGraph(
+		"G",
+		nil,
+		edgeList,
+		dot.WithGraphSubgraphList(subgraphList),
+		dot.WithGraphAttrList(dot.NewAttrList().
+			Add(dot.NewAttr("newrank", "true"))),
+	)
+}
+
+func (*fileDotWriter) interFileDeps(deps []stat.FileDep) []stat.FileDep {
+	r := []stat.FileDep{}
+	for _, x := range deps {
+		if x.Ref().ID() != x.Def().ID() {
+			r = append(r, x)
+		}
+	}
+	return r
+}
+
+func (*fileDotWriter) nodeLabel(st stat.FileStat) string {
+	return fmt.Sprintf("<\n%s\n>", generateNodeLabelHTML(
+		"file", st.File().Name(),
+		st.Ref(), st.Def(),
+		st.UniqRef(), st.UniqDef(),
+	))
+}
+
+func (*fileDotWriter) nodeValue(st stat.FileStat, metricValues map[string]int) int {
+	if metricValues != nil {
+		return metricValues[st.File().ID()]
+	}
+	return st.Weight()
+}
+
+func (*fileDotWriter) edgeValue(dep stat.FileDep, metricValues map[string]int) int {
+	if metricValues != nil {
+		return metricValues[dep.Def().ID()]
+	}
+	return dep.Weight()
+}
+
+func (s *fileDotWriter) fontsizeRanking(stats []stat.FileStat, metricValues map[string]int) *attrRanking {
+	r := util.NewRanking()
+	for _, x := range stats {
+		r.Add(s.nodeValue(x, metricValues))
+	}
+	return s.conf.newFontsizeRanking(r)
+}
+
+func (s *fileDotWriter) weightRanking(deps []stat.FileDep) *attrRanking {
+	r := util.NewRanking()
+	for _, x := range deps {
+		r.Add(x.Weight())
+	}
+	return s.conf.newWeightRanking(r)
+}
+
+func (s *fileDotWriter) penwidthRanking(deps []stat.FileDep, metricValues map[string]int) *attrRanking {
+	r := util.NewRanking()
+	for _, x := range deps {
+		r.Add(s.edgeValue(x, metricValues))
+	}
+	return s.conf.newPenwidthRanking(r)
+}
+
+// NewFileJSONWriter returns a writer that writes the source files and the dependencies between them as a JSON.
+func NewFileJSONWriter(w io.Writer) Writer {
+	return &fileJSONWriter{
+		w:        w,
+		depCalc:  stat.NewFileDepCalculator(),
+		statCalc: stat.NewFileStatCalculator(),
+	}
+}
+
+type fileJSONWriter struct {
+	w        io.Writer
+	depCalc  stat.FileDepCalculator
+	statCalc stat.FileStatCalculator
+}
+
+func (s *fileJSONWriter) Write(node search.Use) error {
+	ref, def := newFileUse(node)
+	s.depCalc.Add(ref, def)
+	s.statCalc.Add(ref, def)
+	return nil
+}
+
+func (s *fileJSONWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("FileJSONWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *fileJSONWriter) flush() error {
+	b, err := json.Marshal(map[string]interface{}{
+		"files": s.statCalc.Result(),
+		"deps":  s.depCalc.Result(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.w, string(b))
+	return err
+}
@@ -0,0 +1,198 @@
+package display
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/berquerant/gotypegraph/search"
+)
+
+type (
+	gexf struct {
+		XMLName xml.Name   `xml:"gexf"`
+		Xmlns   string     `xml:"xmlns,attr"`
+		Version string     `xml:"version,attr"`
+		Graph   *gexfGraph `xml:"graph"`
+	}
+
+	gexfGraph struct {
+		DefaultEdgeType string          `xml:"defaultedgetype,attr"`
+		Mode            string          `xml:"mode,attr"`
+		Attributes      *gexfAttributes `xml:"attributes"`
+		Nodes           []*gexfNode     `xml:"nodes>node"`
+		Edges           []*gexfEdge     `xml:"edges>edge"`
+	}
+
+	gexfAttributes struct {
+		Class      string           `xml:"class,attr"`
+		Attributes []*gexfAttribute `xml:"attribute"`
+	}
+
+	gexfAttribute struct {
+		ID    string `xml:"id,attr"`
+		Title string `xml:"title,attr"`
+		Type  string `xml:"type,attr"`
+	}
+
+	gexfNode struct {
+		ID        string          `xml:"id,attr"`
+		Label     string          `xml:"label,attr"`
+		Pid       string          `xml:"pid,attr,omitempty"`
+		AttValues []*gexfAttValue `xml:"attvalues>attvalue"`
+	}
+
+	gexfAttValue struct {
+		For   string `xml:"for,attr"`
+		Value string `xml:"value,attr"`
+	}
+
+	gexfEdge struct {
+		ID     string `xml:"id,attr"`
+		Source string `xml:"source,attr"`
+		Target string `xml:"target,attr"`
+		Weight int    `xml:"weight,attr"`
+	}
+)
+
+const (
+	gexfNamespace = "http://gexf.net/1.3"
+	gexfVersion   = "1.3"
+)
+
+func gexfNodeAttributes() *gexfAttributes {
+	attrs := []*gexfAttribute{
+		{ID: "package", Title: "package", Type: "string"},
+		{ID: "name", Title: "name", Type: "string"},
+		{ID: "type", Title: "type", Type: "string"},
+		{ID: "recv", Title: "recv", Type: "string"},
+		{ID: "ref", Title: "Ref", Type: "integer"},
+		{ID: "def", Title: "Def", Type: "integer"},
+		{ID: "uniqref", Title: "UniqRef", Type: "integer"},
+		{ID: "uniqdef", Title: "UniqDef", Type: "integer"},
+	}
+	for _, m := range graph.Metrics() {
+		attrs = append(attrs, &gexfAttribute{
+			ID:    m.String(),
+			Title: m.String(),
+			Type:  "double",
+		})
+	}
+	return &gexfAttributes{
+		Class:      "node",
+		Attributes: attrs,
+	}
+}
+
+// NewNodeGEXFWriter returns a writer that writes the definitions as a GEXF,
+// the packages are the parents of the definitions.
+func NewNodeGEXFWriter(w io.Writer) Writer {
+	return &gexfWriter{
+		w:    w,
+		calc: newNodeElementsCalculator(true),
+	}
+}
+
+// NewPackageGEXFWriter returns a writer that writes the packages as a GEXF.
+func NewPackageGEXFWriter(w io.Writer) Writer {
+	return &gexfWriter{
+		w:    w,
+		calc: newPackageElementsCalculator(true),
+	}
+}
+
+type gexfWriter struct {
+	w    io.Writer
+	calc graphElementsCalculator
+}
+
+func (s *gexfWriter) Write(node search.Use) error {
+	s.calc.Add(node)
+	return nil
+}
+
+func (s *gexfWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("GEXFWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *gexfWriter) flush() error {
+	if _, err := io.WriteString(s.w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(s.w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(s.build()); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(s.w)
+	return err
+}
+
+func (s *gexfWriter) build() *gexf {
+	var (
+		elements = s.calc.Result()
+		g        = &gexfGraph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes:      gexfNodeAttributes(),
+		}
+	)
+	for _, x := range elements.parents {
+		g.Nodes = append(g.Nodes, &gexfNode{
+			ID:    x.id,
+			Label: x.label,
+			AttValues: []*gexfAttValue{
+				{For: "package", Value: x.pkg},
+				{For: "name", Value: x.name},
+			},
+		})
+	}
+	for _, x := range elements.nodes {
+		values := []*gexfAttValue{
+			{For: "package", Value: x.pkg},
+			{For: "name", Value: x.name},
+		}
+		if x.typ != "" {
+			values = append(values, &gexfAttValue{For: "type", Value: x.typ})
+		}
+		if x.recv != "" {
+			values = append(values, &gexfAttValue{For: "recv", Value: x.recv})
+		}
+		values = append(values,
+			&gexfAttValue{For: "ref", Value: strconv.Itoa(x.ref)},
+			&gexfAttValue{For: "def", Value: strconv.Itoa(x.def)},
+			&gexfAttValue{For: "uniqref", Value: strconv.Itoa(x.uniqRef)},
+			&gexfAttValue{For: "uniqdef", Value: strconv.Itoa(x.uniqDef)},
+		)
+		for _, m := range graph.Metrics() {
+			values = append(values, &gexfAttValue{
+				For:   m.String(),
+				Value: strconv.FormatFloat(x.metrics[m.String()], 'g', -1, 64),
+			})
+		}
+		g.Nodes = append(g.Nodes, &gexfNode{
+			ID:        x.id,
+			Label:     x.label,
+			Pid:       x.parent,
+			AttValues: values,
+		})
+	}
+	for _, x := range elements.edges {
+		g.Edges = append(g.Edges, &gexfEdge{
+			ID:     x.id,
+			Source: x.source,
+			Target: x.target,
+			Weight: x.weight,
+		})
+	}
+	return &gexf{
+		Xmlns:   gexfNamespace,
+		Version: gexfVersion,
+		Graph:   g,
+	}
+}
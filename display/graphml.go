@@ -0,0 +1,238 @@
+package display
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/berquerant/gotypegraph/search"
+)
+
+type (
+	graphML struct {
+		XMLName xml.Name      `xml:"graphml"`
+		Xmlns   string        `xml:"xmlns,attr"`
+		Keys    []*graphMLKey `xml:"key"`
+		Graph   *graphMLGraph `xml:"graph"`
+	}
+
+	graphMLKey struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+
+	graphMLGraph struct {
+		ID          string         `xml:"id,attr"`
+		EdgeDefault string         `xml:"edgedefault,attr"`
+		Nodes       []*graphMLNode `xml:"node"`
+		Edges       []*graphMLEdge `xml:"edge"`
+	}
+
+	graphMLNode struct {
+		ID    string         `xml:"id,attr"`
+		Data  []*graphMLData `xml:"data"`
+		Graph *graphMLGraph  `xml:"graph,omitempty"`
+	}
+
+	graphMLEdge struct {
+		ID     string         `xml:"id,attr"`
+		Source string         `xml:"source,attr"`
+		Target string         `xml:"target,attr"`
+		Data   []*graphMLData `xml:"data"`
+	}
+
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+var (
+	graphMLNodeKeys = []*graphMLKey{
+		{ID: "package", For: "node", Name: "package", Type: "string"},
+		{ID: "name", For: "node", Name: "name", Type: "string"},
+		{ID: "type", For: "node", Name: "type", Type: "string"},
+		{ID: "recv", For: "node", Name: "recv", Type: "string"},
+		{ID: "position", For: "node", Name: "position", Type: "string"},
+		{ID: "ref", For: "node", Name: "Ref", Type: "int"},
+		{ID: "def", For: "node", Name: "Def", Type: "int"},
+		{ID: "uniqref", For: "node", Name: "UniqRef", Type: "int"},
+		{ID: "uniqdef", For: "node", Name: "UniqDef", Type: "int"},
+	}
+	graphMLEdgeKeys = []*graphMLKey{
+		{ID: "weight", For: "edge", Name: "weight", Type: "int"},
+	}
+)
+
+func newGraphML(nodes []*graphMLNode, edges []*graphMLEdge) *graphML {
+	return &graphML{
+		Xmlns: graphMLNamespace,
+		Keys:  append(append([]*graphMLKey{}, graphMLNodeKeys...), graphMLEdgeKeys...),
+		Graph: &graphMLGraph{
+			ID:          "G",
+			EdgeDefault: "directed",
+			Nodes:       nodes,
+			Edges:       edges,
+		},
+	}
+}
+
+func writeGraphML(w io.Writer, g *graphML) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(g); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func graphMLCountData(ref, def, uniqRef, uniqDef int) []*graphMLData {
+	return []*graphMLData{
+		{Key: "ref", Value: strconv.Itoa(ref)},
+		{Key: "def", Value: strconv.Itoa(def)},
+		{Key: "uniqref", Value: strconv.Itoa(uniqRef)},
+		{Key: "uniqdef", Value: strconv.Itoa(uniqDef)},
+	}
+}
+
+func newGraphMLEdge(x *graphElementEdge) *graphMLEdge {
+	return &graphMLEdge{
+		ID:     x.id,
+		Source: x.source,
+		Target: x.target,
+		Data: []*graphMLData{
+			{Key: "weight", Value: strconv.Itoa(x.weight)},
+		},
+	}
+}
+
+func newGraphMLEdges(elements *graphElements) []*graphMLEdge {
+	edges := make([]*graphMLEdge, len(elements.edges))
+	for i, x := range elements.edges {
+		edges[i] = newGraphMLEdge(x)
+	}
+	return edges
+}
+
+// NewNodeGraphMLWriter returns a writer that writes the definitions as a GraphML,
+// the definitions are in the nested graphs of the packages.
+func NewNodeGraphMLWriter(w io.Writer) Writer {
+	return &nodeGraphMLWriter{
+		w:    w,
+		calc: newNodeElementsCalculator(false),
+	}
+}
+
+type nodeGraphMLWriter struct {
+	w    io.Writer
+	calc graphElementsCalculator
+}
+
+func (s *nodeGraphMLWriter) Write(node search.Use) error {
+	s.calc.Add(node)
+	return nil
+}
+
+func (s *nodeGraphMLWriter) Flush() error {
+	if err := writeGraphML(s.w, s.build()); err != nil {
+		return fmt.Errorf("NodeGraphMLWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *nodeGraphMLWriter) build() *graphML {
+	var (
+		elements = s.calc.Result()
+		nodes    = make([]*graphMLNode, len(elements.parents))
+		graphs   = make(map[string]*graphMLGraph, len(elements.parents))
+	)
+	for i, x := range elements.parents {
+		graph := &graphMLGraph{
+			ID:          x.id + ":",
+			EdgeDefault: "directed",
+		}
+		graphs[x.id] = graph
+		nodes[i] = &graphMLNode{
+			ID: x.id,
+			Data: []*graphMLData{
+				{Key: "package", Value: x.pkg},
+				{Key: "name", Value: x.name},
+			},
+			Graph: graph,
+		}
+	}
+	for _, x := range elements.nodes {
+		graph := graphs[x.parent]
+		graph.Nodes = append(graph.Nodes, &graphMLNode{
+			ID:   x.id,
+			Data: s.nodeData(x),
+		})
+	}
+	return newGraphML(nodes, newGraphMLEdges(elements))
+}
+
+func (*nodeGraphMLWriter) nodeData(x *graphElementNode) []*graphMLData {
+	data := []*graphMLData{
+		{Key: "package", Value: x.pkg},
+		{Key: "name", Value: x.name},
+		{Key: "type", Value: x.typ},
+	}
+	if x.recv != "" {
+		data = append(data, &graphMLData{Key: "recv", Value: x.recv})
+	}
+	if x.position != "" {
+		data = append(data, &graphMLData{Key: "position", Value: x.position})
+	}
+	return append(data, graphMLCountData(x.ref, x.def, x.uniqRef, x.uniqDef)...)
+}
+
+// NewPackageGraphMLWriter returns a writer that writes the packages as a GraphML.
+func NewPackageGraphMLWriter(w io.Writer) Writer {
+	return &packageGraphMLWriter{
+		w:    w,
+		calc: newPackageElementsCalculator(false),
+	}
+}
+
+type packageGraphMLWriter struct {
+	w    io.Writer
+	calc graphElementsCalculator
+}
+
+func (s *packageGraphMLWriter) Write(node search.Use) error {
+	s.calc.Add(node)
+	return nil
+}
+
+func (s *packageGraphMLWriter) Flush() error {
+	if err := writeGraphML(s.w, s.build()); err != nil {
+		return fmt.Errorf("PackageGraphMLWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *packageGraphMLWriter) build() *graphML {
+	var (
+		elements = s.calc.Result()
+		nodes    = make([]*graphMLNode, len(elements.nodes))
+	)
+	for i, x := range elements.nodes {
+		nodes[i] = &graphMLNode{
+			ID: x.id,
+			Data: append([]*graphMLData{
+				{Key: "package", Value: x.pkg},
+				{Key: "name", Value: x.name},
+			}, graphMLCountData(x.ref, x.def, x.uniqRef, x.uniqDef)...),
+		}
+	}
+	return newGraphML(nodes, newGraphMLEdges(elements))
+}
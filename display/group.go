@@ -0,0 +1,240 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/dot"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/berquerant/gotypegraph/util"
+)
+
+// NewGroupDotWriter returns a writer that draws the dependencies between the groups of the packages.
+// The dependencies in a group are counted but not drawn.
+func NewGroupDotWriter(w io.Writer, groupOf stat.GroupFunc, opt ...WriterOption) Writer {
+	conf := newWriterConfig()
+	for _, x := range opt {
+		x(conf)
+	}
+	return &groupDotWriter{
+		w:           w,
+		depCalc:     stat.NewGroupDepCalculator(groupOf),
+		statDepCalc: stat.NewGroupStatCalculator(groupOf),
+		conf:        conf,
+	}
+}
+
+type groupDotWriter struct {
+	w           io.Writer
+	depCalc     stat.GroupDepCalculator
+	statDepCalc stat.GroupStatCalculator
+	conf        *WriterConfig
+}
+
+func (s *groupDotWriter) Write(node search.Use) error {
+	var (
+		ref = stat.NewPkg(node.Ref().Pkg())
+		def = stat.NewPkg(node.Def().Pkg())
+	)
+	s.depCalc.Add(ref, def)
+	s.statDepCalc.Add(ref, def)
+	return nil
+}
+
+func (s *groupDotWriter) Flush() error {
+	if _, err := fmt.Fprintln(s.w, s.build().String()); err != nil {
+		return fmt.Errorf("GroupDotWriter: %w", err)
+	}
+	return nil
+}
+
+var groupNodeIDReplacer = regexp.MustCompile(`\W`)
+
+// nodeID returns the id of the group that can be used in dot.
+func (*groupDotWriter) nodeID(group stat.Group) dot.ID {
+	return dot.ID("group_" + groupNodeIDReplacer.ReplaceAllString(group.ID(), "_"))
+}
+
+func (s *groupDotWriter) build() dot.Graph {
+	var (
+		deps  = s.interGroupDeps(s.depCalc.Result())
+		stats = s.statDepCalc.Result()
+
+		nodeList = dot.NewNodeList()
+
+		metricValues    = s.conf.metricValues(stat.NewGroupGraph(deps))
+		fontsizeRanking = s.fontsizeRanking(stats, metricValues)
+	)
+
+	for _, st := range stats.Stats() {
+		var (
+			fontsize = fontsizeRanking.get(s.nodeValue(st, metricValues))
+			attrList = dot.NewAttrList().
+					Add(dot.NewAttr("shape", "box")).
+					Add(dot.NewAttr("label", s.nodeLabel(st), dot.WithAttrRaw(true))).
+					Add(dot.NewAttr("tooltip", s.nodeTooltip(st))).
+					Add(dot.NewAttr("fontsize", strconv.Itoa(fontsize)))
+		)
+		nodeList.Add(dot.NewNode(s.nodeID(st.Group()), dot.WithNodeAttrList(attrList)))
+	}
+
+	var (
+		edgeList = dot.NewEdgeList()
+
+		penwidthRanking = s.penwidthRanking(deps, metricValues)
+		weightRanking   = s.weightRanking(deps)
+	)
+
+	for _, dep := range deps {
+		var (
+			penwidth  = penwidthRanking.get(s.edgeValue(dep, metricValues))
+			arrowsize = float64(penwidth) / 2
+			weight    = weightRanking.get(dep.Weight())
+			tooltip   = fmt.Sprintf("%s -> %s [%d]", dep.Ref().ID(), dep.Def().ID(), dep.Weight())
+			attrList  = dot.NewAttrList().
+					Add(dot.NewAttr("label", strconv.Itoa(dep.Weight()))).
+					Add(dot.NewAttr("tooltip", tooltip)).
+					Add(dot.NewAttr("labeltooltip", tooltip)).
+					Add(dot.NewAttr("penwidth", strconv.Itoa(penwidth))).
+					Add(dot.NewAttr("arrowsize", fmt.Sprint(arrowsize))).
+					Add(dot.NewAttr("weight", strconv.Itoa(weight)))
+		)
+		edgeList.Add(dot.NewEdge(
+			s.nodeID(dep.Ref()),
+			s.nodeID(dep.Def()),
+			dot.WithEdgeAttrList(attrList),
+		))
+	}
+	return dot.NewGraph("G", nodeList, edgeList)
+}
+
+func (*groupDotWriter) interGroupDeps(deps []stat.GroupDep) []stat.GroupDep {
+	r := []stat.GroupDep{}
+	for _, x := range deps {
+		if x.Ref().ID() != x.Def().ID() {
+			r = append(r, x)
+		}
+	}
+	return r
+}
+
+func (*groupDotWriter) nodeLabel(st stat.GroupStat) string {
+	titleKey, titleValue := "group", st.Group().ID()
+	if m, ok := st.Group().(stat.ModuleGroup); ok {
+		titleKey = "module"
+		if m.Version() != "" {
+			titleValue = html.EscapeString(titleValue + "@" + m.Version())
+		}
+	}
+	return fmt.Sprintf("<\n%s\n>", generateNodeLabelHTML(
+		titleKey, titleValue,
+		st.Refs().Weight(), st.Defs().Weight(),
+		len(st.Refs().Deps()), len(st.Defs().Deps()),
+		&nodeLabelRow{key: "Pkgs", value: len(st.Pkgs())},
+	))
+}
+
+func (*groupDotWriter) nodeTooltip(st stat.GroupStat) string {
+	pkgs := make([]string, len(st.Pkgs()))
+	for i, x := range st.Pkgs() {
+		pkgs[i] = x.ID()
+	}
+	tooltip := newRefDefTooltip(fmt.Sprintf("%s\n%s", st.Group().ID(), strings.Join(pkgs, "\n")))
+	for _, x := range st.Refs().Deps() {
+		tooltip.addRef(newRefDefTooltipElem(x.Group().ID(), x.Weight()))
+	}
+	for _, x := range st.Defs().Deps() {
+		tooltip.addDef(newRefDefTooltipElem(x.Group().ID(), x.Weight()))
+	}
+	return tooltip.String()
+}
+
+func (*groupDotWriter) nodeValue(st stat.GroupStat, metricValues map[string]int) int {
+	if metricValues != nil {
+		return metricValues[st.Group().ID()]
+	}
+	return st.Weight()
+}
+
+func (*groupDotWriter) edgeValue(dep stat.GroupDep, metricValues map[string]int) int {
+	if metricValues != nil {
+		return metricValues[dep.Def().ID()]
+	}
+	return dep.Weight()
+}
+
+func (s *groupDotWriter) fontsizeRanking(stats stat.GroupStatSet, metricValues map[string]int) *attrRanking {
+	r := util.NewRanking()
+	for _, x := range stats.Stats() {
+		r.Add(s.nodeValue(x, metricValues))
+	}
+	return s.conf.newFontsizeRanking(r)
+}
+
+func (s *groupDotWriter) weightRanking(deps []stat.GroupDep) *attrRanking {
+	r := util.NewRanking()
+	for _, x := range deps {
+		r.Add(x.Weight())
+	}
+	return s.conf.newWeightRanking(r)
+}
+
+func (s *groupDotWriter) penwidthRanking(deps []stat.GroupDep, metricValues map[string]int) *attrRanking {
+	r := util.NewRanking()
+	for _, x := range deps {
+		r.Add(s.edgeValue(x, metricValues))
+	}
+	return s.conf.newPenwidthRanking(r)
+}
+
+// NewGroupJSONWriter returns a writer that writes the groups and the dependencies between them as a JSON.
+func NewGroupJSONWriter(w io.Writer, groupOf stat.GroupFunc) Writer {
+	return &groupJSONWriter{
+		w:           w,
+		depCalc:     stat.NewGroupDepCalculator(groupOf),
+		statDepCalc: stat.NewGroupStatCalculator(groupOf),
+	}
+}
+
+type groupJSONWriter struct {
+	w           io.Writer
+	depCalc     stat.GroupDepCalculator
+	statDepCalc stat.GroupStatCalculator
+}
+
+func (s *groupJSONWriter) Write(node search.Use) error {
+	var (
+		ref = stat.NewPkg(node.Ref().Pkg())
+		def = stat.NewPkg(node.Def().Pkg())
+	)
+	s.depCalc.Add(ref, def)
+	s.statDepCalc.Add(ref, def)
+	return nil
+}
+
+func (s *groupJSONWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("GroupJSONWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *groupJSONWriter) flush() error {
+	deps := s.depCalc.Result()
+	sortGroupDeps(deps)
+	b, err := json.Marshal(map[string]interface{}{
+		"groups": s.statDepCalc.Result(),
+		"deps":   deps,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.w, string(b))
+	return err
+}
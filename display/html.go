@@ -0,0 +1,129 @@
+package display
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/search"
+)
+
+//go:embed viewer.html
+var htmlViewer string
+
+// htmlDataPlaceholder is replaced with the graph data in the viewer.
+const htmlDataPlaceholder = "/*DATA*/null"
+
+// NewNodeHTMLWriter returns a writer that writes a self-contained HTML viewer of the definitions.
+// The viewer works offline, searches the definitions by name, highlights the neighbours of the clicked node,
+// collapses and expands the packages and shows the details of the definitions including the source positions.
+func NewNodeHTMLWriter(w io.Writer) Writer {
+	return &htmlWriter{
+		w:    w,
+		calc: newNodeElementsCalculator(true),
+	}
+}
+
+// NewPackageHTMLWriter returns a writer that writes a self-contained HTML viewer of the packages.
+func NewPackageHTMLWriter(w io.Writer) Writer {
+	return &htmlWriter{
+		w:    w,
+		calc: newPackageElementsCalculator(true),
+	}
+}
+
+type htmlWriter struct {
+	w    io.Writer
+	calc graphElementsCalculator
+}
+
+func (s *htmlWriter) Write(node search.Use) error {
+	s.calc.Add(node)
+	return nil
+}
+
+func (s *htmlWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("HTMLWriter: %w", err)
+	}
+	return nil
+}
+
+type (
+	htmlData struct {
+		Parents []*htmlNode `json:"parents"`
+		Nodes   []*htmlNode `json:"nodes"`
+		Edges   []*htmlEdge `json:"edges"`
+	}
+
+	htmlNode struct {
+		ID       string             `json:"id"`
+		Label    string             `json:"label"`
+		Parent   string             `json:"parent,omitempty"`
+		Pkg      string             `json:"pkg"`
+		Type     string             `json:"type,omitempty"`
+		Recv     string             `json:"recv,omitempty"`
+		Position string             `json:"position,omitempty"`
+		Ref      int                `json:"ref"`
+		Def      int                `json:"def"`
+		UniqRef  int                `json:"uniqref"`
+		UniqDef  int                `json:"uniqdef"`
+		Metrics  map[string]float64 `json:"metrics,omitempty"`
+	}
+
+	htmlEdge struct {
+		Source string `json:"source"`
+		Target string `json:"target"`
+		Weight int    `json:"weight"`
+	}
+)
+
+func newHTMLNode(x *graphElementNode) *htmlNode {
+	return &htmlNode{
+		ID:       x.id,
+		Label:    x.label,
+		Parent:   x.parent,
+		Pkg:      x.pkg,
+		Type:     x.typ,
+		Recv:     x.recv,
+		Position: x.position,
+		Ref:      x.ref,
+		Def:      x.def,
+		UniqRef:  x.uniqRef,
+		UniqDef:  x.uniqDef,
+		Metrics:  x.metrics,
+	}
+}
+
+func (s *htmlWriter) flush() error {
+	var (
+		elements = s.calc.Result()
+		data     = &htmlData{
+			Parents: []*htmlNode{},
+			Nodes:   []*htmlNode{},
+			Edges:   []*htmlEdge{},
+		}
+	)
+	for _, x := range elements.parents {
+		data.Parents = append(data.Parents, newHTMLNode(x))
+	}
+	for _, x := range elements.nodes {
+		data.Nodes = append(data.Nodes, newHTMLNode(x))
+	}
+	for _, x := range elements.edges {
+		data.Edges = append(data.Edges, &htmlEdge{
+			Source: x.source,
+			Target: x.target,
+			Weight: x.weight,
+		})
+	}
+	// json.Marshal escapes <, > and & so the data cannot close the script element
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(s.w, strings.Replace(htmlViewer, htmlDataPlaceholder, string(b), 1))
+	return err
+}
@@ -71,11 +71,23 @@ func newRef(node search.RefNode) *Ref {
 	}
 }
 
+// objStringer is a node that keeps the string of the object, e.g. the nodes restored by Reader.
+type objStringer interface {
+	ObjString() string
+}
+
+func objString(node search.Node) string {
+	if x, ok := node.(objStringer); ok {
+		return x.ObjString()
+	}
+	return types.ObjectString(node.Obj().(types.Object), nil)
+}
+
 func newObj(node search.Node) *Obj {
 	return &Obj{
 		Recv: node.RecvString(search.WithNodeRawRecv(true)),
 		Type: node.Type().String(),
-		Str:  types.ObjectString(node.Obj().(types.Object), nil),
+		Str:  objString(node),
 		P:    newPos(node.Obj().Pos(), node.Pkg()),
 		Name: node.Name(),
 	}
@@ -0,0 +1,387 @@
+package jsonify
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/search"
+	"golang.org/x/tools/go/packages"
+)
+
+// Reader reads the uses written as the lines of Use.
+type Reader interface {
+	// Read reads all the uses.
+	Read() ([]search.Use, error)
+}
+
+// NewReader returns a reader that restores the uses from the lines of Use.
+//
+// The packages, the objects and the positions are restored with synthetic values
+// so that the uses work with the writers without loading the source.
+// The objects have no types except the receivers of the methods and the fields of the named types,
+// the nodes keep the strings of the objects for NewUse.
+func NewReader(r io.Reader) Reader {
+	return &reader{
+		r: r,
+	}
+}
+
+type reader struct {
+	r io.Reader
+}
+
+func (s *reader) Read() ([]search.Use, error) {
+	var (
+		uses    []*Use
+		scanner = bufio.NewScanner(s.r)
+		line    int
+	)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line++
+		b := scanner.Bytes()
+		if len(strings.TrimSpace(string(b))) == 0 {
+			continue
+		}
+		var u Use
+		if err := json.Unmarshal(b, &u); err != nil {
+			return nil, fmt.Errorf("Reader: line %d: %w", line, err)
+		}
+		if u.Ref == nil || u.Ref.Pkg == nil || u.Ref.Obj == nil || u.Ref.Ident == nil ||
+			u.Def == nil || u.Def.Pkg == nil || u.Def.Obj == nil {
+			return nil, fmt.Errorf("Reader: line %d: incomplete use", line)
+		}
+		uses = append(uses, &u)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Reader: %w", err)
+	}
+
+	b := newRestorer(uses)
+	b.declareTypes(uses)
+	result := make([]search.Use, len(uses))
+	for i, u := range uses {
+		result[i] = b.use(u)
+	}
+	b.complete()
+	return result, nil
+}
+
+type (
+	position struct {
+		filename string
+		line     int
+		column   int
+	}
+
+	// restorer creates the synthetic packages and objects.
+	restorer struct {
+		fset  *token.FileSet
+		files map[string]*token.File
+		// lineWidths are the widths of the lines by filename.
+		lineWidths map[string][]int
+		// loaded are the paths of the packages that were loaded, the others are foreign.
+		loaded map[string]bool
+		pkgs   map[string]search.Pkg
+		tpkgs  map[string]*types.Package
+		objs   map[string]search.Object
+		// fields are the fields of the named types.
+		fields map[*types.Named][]*types.Var
+	}
+)
+
+// parsePosition parses file:line:column.
+func parsePosition(s string) (*position, bool) {
+	var (
+		xs = strings.Split(s, ":")
+		n  = len(xs)
+	)
+	if n < 3 {
+		return nil, false
+	}
+	line, err := strconv.Atoi(xs[n-2])
+	if err != nil || line < 1 {
+		return nil, false
+	}
+	column, err := strconv.Atoi(xs[n-1])
+	if err != nil || column < 1 {
+		return nil, false
+	}
+	return &position{
+		filename: strings.Join(xs[:n-2], ":"),
+		line:     line,
+		column:   column,
+	}, true
+}
+
+func newRestorer(uses []*Use) *restorer {
+	s := &restorer{
+		fset:       token.NewFileSet(),
+		files:      map[string]*token.File{},
+		lineWidths: map[string][]int{},
+		loaded:     map[string]bool{},
+		pkgs:       map[string]search.Pkg{},
+		tpkgs:      map[string]*types.Package{},
+		objs:       map[string]search.Object{},
+		fields:     map[*types.Named][]*types.Var{},
+	}
+	for _, u := range uses {
+		s.loaded[u.Ref.Pkg.Path] = true
+		for _, p := range []*Pos{u.Ref.Ident.P, u.Ref.Obj.P} {
+			if p != nil {
+				s.addPosition(p.Position)
+			}
+		}
+		// the positions of the definitions are known only in the loaded packages
+		if p := u.Def.Obj.P; p != nil && s.addPosition(p.Position) {
+			s.loaded[u.Def.Pkg.Path] = true
+		}
+	}
+	s.addFiles()
+	return s
+}
+
+func (s *restorer) addPosition(v string) bool {
+	p, ok := parsePosition(v)
+	if !ok {
+		return false
+	}
+	widths := s.lineWidths[p.filename]
+	for len(widths) < p.line {
+		widths = append(widths, 1)
+	}
+	if w := widths[p.line-1]; p.column+1 > w {
+		widths[p.line-1] = p.column + 1
+	}
+	s.lineWidths[p.filename] = widths
+	return true
+}
+
+// addFiles adds the files whose lines are wide enough for the positions.
+func (s *restorer) addFiles() {
+	filenames := make([]string, 0, len(s.lineWidths))
+	for x := range s.lineWidths {
+		filenames = append(filenames, x)
+	}
+	sort.Strings(filenames)
+	for _, name := range filenames {
+		var (
+			widths = s.lineWidths[name]
+			lines  = make([]int, len(widths))
+			size   int
+		)
+		for i, w := range widths {
+			lines[i] = size
+			size += w
+		}
+		f := s.fset.AddFile(name, -1, size)
+		f.SetLines(lines)
+		s.files[name] = f
+	}
+}
+
+func (s *restorer) pos(p *Pos) token.Pos {
+	if p == nil {
+		return token.NoPos
+	}
+	x, ok := parsePosition(p.Position)
+	if !ok {
+		return token.NoPos
+	}
+	f, ok := s.files[x.filename]
+	if !ok {
+		return token.NoPos
+	}
+	return f.LineStart(x.line) + token.Pos(x.column-1)
+}
+
+const builtinPkgPath = "builtin"
+
+func (s *restorer) pkg(p *Pkg) search.Pkg {
+	if x, ok := s.pkgs[p.Path]; ok {
+		return x
+	}
+	var x search.Pkg
+	switch {
+	case p.Path == builtinPkgPath:
+		x = search.NewBuiltinPkg()
+	case s.loaded[p.Path]:
+		x = search.NewPkg(&packages.Package{
+			ID:      p.Path,
+			Name:    p.Name,
+			PkgPath: p.Path,
+			Fset:    s.fset,
+			Types:   s.typesPkg(p),
+		})
+	default:
+		x = search.NewPkgWithName(p.Name, p.Path)
+	}
+	s.pkgs[p.Path] = x
+	return x
+}
+
+func (s *restorer) typesPkg(p *Pkg) *types.Package {
+	if x, ok := s.tpkgs[p.Path]; ok {
+		return x
+	}
+	x := types.NewPackage(p.Path, p.Name)
+	s.tpkgs[p.Path] = x
+	return x
+}
+
+func (s *restorer) use(u *Use) search.Use {
+	var (
+		refPkg = s.pkg(u.Ref.Pkg)
+		defPkg = s.pkg(u.Def.Pkg)
+		ident  = ast.NewIdent(u.Ref.Ident.Name)
+	)
+	ident.NamePos = s.pos(u.Ref.Ident.P)
+	return search.NewUse(
+		&refNode{
+			RefNode: search.NewRefNode(
+				refPkg,
+				s.obj(refPkg, u.Ref.Obj),
+				&search.NodeInfo{
+					ValueSpecIndex: -1,
+				},
+				nil,
+				ident,
+			),
+			str: u.Ref.Obj.Str,
+		},
+		&defNode{
+			DefNode: search.NewDefNode(
+				defPkg,
+				s.obj(defPkg, u.Def.Obj),
+				s.defInfo(u.Def.Obj),
+			),
+			str: u.Def.Obj.Str,
+		},
+	)
+}
+
+type (
+	// refNode is a restored RefNode that keeps the string of the object
+	// because the restored objects have no types.
+	refNode struct {
+		search.RefNode
+		str string
+	}
+
+	// defNode is a restored DefNode that keeps the string of the object.
+	defNode struct {
+		search.DefNode
+		str string
+	}
+)
+
+func (s *refNode) ObjString() string { return s.str }
+func (s *defNode) ObjString() string { return s.str }
+
+// declareTypes creates the named types before the methods and the fields refer them without positions.
+func (s *restorer) declareTypes(uses []*Use) {
+	typ := search.TypeNodeType.String()
+	for _, u := range uses {
+		if u.Ref.Obj.Type == typ {
+			s.obj(s.pkg(u.Ref.Pkg), u.Ref.Obj)
+		}
+		if u.Def.Obj.Type == typ {
+			s.obj(s.pkg(u.Def.Pkg), u.Def.Obj)
+		}
+	}
+}
+
+func (*restorer) defInfo(obj *Obj) *search.NodeInfo {
+	info := &search.NodeInfo{
+		ValueSpecIndex: -1,
+	}
+	if obj.Type == search.FieldNodeType.String() {
+		info.Recv = obj.Recv
+	}
+	return info
+}
+
+// named returns the named type in the package, creates it if not found.
+func (*restorer) named(pkg *types.Package, name string, pos token.Pos) *types.Named {
+	if x, ok := pkg.Scope().Lookup(name).(*types.TypeName); ok {
+		if t, ok := x.Type().(*types.Named); ok {
+			return t
+		}
+	}
+	t := types.NewNamed(types.NewTypeName(pos, pkg, name, nil), types.NewStruct(nil, nil), nil)
+	pkg.Scope().Insert(t.Obj())
+	return t
+}
+
+func (s *restorer) obj(pkg search.Pkg, obj *Obj) search.Object {
+	key := strings.Join([]string{pkg.Path(), obj.Type, obj.Recv, obj.Name}, "\x00")
+	if x, ok := s.objs[key]; ok {
+		return x
+	}
+	x := s.newObj(pkg, obj)
+	s.objs[key] = x
+	return x
+}
+
+func (s *restorer) newObj(pkg search.Pkg, obj *Obj) search.Object {
+	pos := s.pos(obj.P)
+	if pkg.IsBuiltin() {
+		if x := types.Universe.Lookup(obj.Name); x != nil {
+			return x
+		}
+		return types.NewLabel(pos, nil, obj.Name)
+	}
+
+	var (
+		tpkg    = s.typesPkg(&Pkg{Name: pkg.Name(), Path: pkg.Path()})
+		invalid = types.Typ[types.Invalid]
+	)
+	switch obj.Type {
+	case search.FuncNodeType.String():
+		return types.NewFunc(pos, tpkg, obj.Name, types.NewSignature(nil, nil, nil, false))
+	case search.MethodNodeType.String():
+		var (
+			named            = s.named(tpkg, obj.Recv, token.NoPos)
+			recvT types.Type = named
+		)
+		if strings.HasPrefix(obj.Str, "func (*") {
+			recvT = types.NewPointer(named)
+		}
+		f := types.NewFunc(pos, tpkg, obj.Name, types.NewSignature(types.NewVar(token.NoPos, tpkg, "", recvT), nil, nil, false))
+		named.AddMethod(f)
+		return f
+	case search.TypeNodeType.String():
+		t := s.named(tpkg, obj.Name, pos)
+		return t.Obj()
+	case search.VarNodeType.String():
+		return types.NewVar(pos, tpkg, obj.Name, invalid)
+	case search.ConstNodeType.String():
+		return types.NewConst(pos, tpkg, obj.Name, invalid, constant.MakeUnknown())
+	case search.FieldNodeType.String():
+		f := types.NewField(pos, tpkg, obj.Name, invalid, false)
+		if obj.Recv != "" {
+			named := s.named(tpkg, obj.Recv, token.NoPos)
+			s.fields[named] = append(s.fields[named], f)
+		}
+		return f
+	default:
+		return types.NewLabel(pos, tpkg, obj.Name)
+	}
+}
+
+// complete sets the fields to the named types.
+func (s *restorer) complete() {
+	for named, fields := range s.fields {
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Name() < fields[j].Name() })
+		named.SetUnderlying(types.NewStruct(fields, nil))
+	}
+}
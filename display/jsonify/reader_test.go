@@ -0,0 +1,156 @@
+package jsonify_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/display/jsonify"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/packages"
+)
+
+type testFile struct {
+	f *token.File
+}
+
+func newTestFile(fset *token.FileSet, name string) *testFile {
+	lines := make([]int, 20)
+	for i := range lines {
+		lines[i] = i * 40
+	}
+	f := fset.AddFile(name, -1, len(lines)*40)
+	f.SetLines(lines)
+	return &testFile{f: f}
+}
+
+func (s *testFile) pos(line, column int) token.Pos { return s.f.LineStart(line) + token.Pos(column-1) }
+
+func newTestUse(refPkg search.Pkg, refObj types.Object, pos token.Pos, defPkg search.Pkg, defObj types.Object, defRecv string) search.Use {
+	ident := ast.NewIdent(defObj.Name())
+	ident.NamePos = pos
+	return search.NewUse(
+		search.NewRefNode(refPkg, refObj, &search.NodeInfo{ValueSpecIndex: -1}, nil, ident),
+		search.NewDefNode(defPkg, defObj, &search.NodeInfo{ValueSpecIndex: -1, Recv: defRecv}),
+	)
+}
+
+func TestReader(t *testing.T) {
+	var (
+		fset = token.NewFileSet()
+		// the filename contains the separator of the position
+		fileA = newTestFile(fset, "C:/src/a/a.go")
+		fileB = newTestFile(fset, "/src/b@v1.0.0/b.go")
+		tpkgA = types.NewPackage("example.com/a", "a")
+		tpkgB = types.NewPackage("example.com/b", "b")
+		pkgA  = search.NewPkg(&packages.Package{ID: "example.com/a", Name: "a", PkgPath: "example.com/a", Fset: fset, Types: tpkgA})
+		pkgB  = search.NewPkg(&packages.Package{ID: "example.com/b", Name: "b", PkgPath: "example.com/b", Fset: fset, Types: tpkgB})
+		fmtP  = search.NewPkgWithName("fmt", "fmt")
+
+		sig  = types.NewSignature(nil, nil, nil, false)
+		x    = types.NewField(fileA.pos(2, 2), tpkgA, "X", types.Typ[types.Int], false)
+		tObj = types.NewTypeName(fileA.pos(1, 6), tpkgA, "T", nil)
+		tT   = types.NewNamed(tObj, types.NewStruct([]*types.Var{x}, nil), nil)
+		m    = types.NewFunc(fileA.pos(4, 13), tpkgA, "M", types.NewSignature(types.NewVar(token.NoPos, tpkgA, "t", types.NewPointer(tT)), nil, nil, false))
+		f    = types.NewFunc(fileA.pos(6, 6), tpkgA, "F", sig)
+		v    = types.NewVar(fileA.pos(7, 5), tpkgA, "V", types.Typ[types.Int])
+		c    = types.NewConst(fileA.pos(8, 7), tpkgA, "C", types.Typ[types.Int], constant.MakeInt64(1))
+		g    = types.NewFunc(fileB.pos(3, 6), tpkgB, "G", sig)
+		p    = types.NewFunc(token.NoPos, types.NewPackage("fmt", "fmt"), "Println", sig)
+
+		uses = []search.Use{
+			newTestUse(pkgA, f, fileA.pos(6, 20), pkgA, tObj, ""),
+			newTestUse(pkgA, f, fileA.pos(6, 30), pkgA, m, ""),
+			newTestUse(pkgA, m, fileA.pos(5, 4), pkgA, x, "T"),
+			newTestUse(pkgA, f, fileA.pos(9, 2), pkgA, v, ""),
+			newTestUse(pkgA, f, fileA.pos(9, 10), pkgA, c, ""),
+			newTestUse(pkgA, f, fileA.pos(10, 2), search.NewBuiltinPkg(), types.Universe.Lookup("len"), ""),
+			newTestUse(pkgA, f, fileA.pos(11, 2), fmtP, p, ""),
+			newTestUse(pkgB, g, fileB.pos(4, 3), pkgA, f, ""),
+		}
+	)
+	tT.AddMethod(m)
+
+	var buf bytes.Buffer
+	for _, u := range uses {
+		b, err := json.Marshal(jsonify.NewUse(u))
+		if !assert.Nil(t, err) {
+			return
+		}
+		buf.Write(b)
+		buf.WriteString("\n")
+	}
+	got, err := jsonify.NewReader(&buf).Read()
+	if !assert.Nil(t, err) || !assert.Equal(t, len(uses), len(got)) {
+		return
+	}
+
+	// pos is not restored as it is
+	normalize := func(u *jsonify.Use) *jsonify.Use {
+		for _, p := range []*jsonify.Pos{u.Ref.Ident.P, u.Ref.Obj.P, u.Def.Obj.P} {
+			p.Pos = 0
+		}
+		return u
+	}
+	for i, want := range uses {
+		g := got[i]
+		assert.Equal(t, stat.NewNode(want.Ref()).ID(), stat.NewNode(g.Ref()).ID(), "%d ref id", i)
+		assert.Equal(t, stat.NewNode(want.Def()).ID(), stat.NewNode(g.Def()).ID(), "%d def id", i)
+		assert.Equal(t, want.Ref().RecvString(), g.Ref().RecvString(), "%d ref recv", i)
+		assert.Equal(t, want.Def().RecvString(), g.Def().RecvString(), "%d def recv", i)
+		assert.Equal(t, want.Ref().Pkg().Pkg() != nil, g.Ref().Pkg().Pkg() != nil, "%d ref loaded", i)
+		assert.Equal(t, want.Def().Pkg().Pkg() != nil, g.Def().Pkg().Pkg() != nil, "%d def loaded", i)
+		assert.Equal(t, want.Def().Pkg().IsBuiltin(), g.Def().Pkg().IsBuiltin(), "%d def builtin", i)
+		assert.Equal(t, normalize(jsonify.NewUse(want)), normalize(jsonify.NewUse(g)), "%d use", i)
+	}
+
+	t.Run("position", func(t *testing.T) {
+		ref := got[0].Ref()
+		assert.Equal(t, "C:/src/a/a.go:6:20", ref.Pkg().Pkg().Fset.Position(ref.Ident().Pos()).String())
+		def := got[7].Def()
+		assert.Equal(t, "C:/src/a/a.go:6:6", def.Pkg().Pkg().Fset.Position(def.Obj().Pos()).String())
+	})
+
+	t.Run("receiver", func(t *testing.T) {
+		typ, ok := got[0].Def().Obj().Type().(*types.Named)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, 1, typ.NumMethods())
+		assert.Same(t, got[1].Def().Obj(), typ.Method(0))
+		assert.Same(t, got[1].Def().Obj(), got[2].Ref().Obj(), "method interned")
+		st, ok := typ.Underlying().(*types.Struct)
+		if !assert.True(t, ok) || !assert.Equal(t, 1, st.NumFields()) {
+			return
+		}
+		assert.Same(t, got[2].Def().Obj(), st.Field(0))
+	})
+}
+
+func TestReaderError(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		input string
+	}{
+		{
+			title: "invalid json",
+			input: "{",
+		},
+		{
+			title: "incomplete",
+			input: `{"ref":{"pkg":{"name":"a","path":"a"}}}`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			_, err := jsonify.NewReader(bytes.NewBufferString(tc.input)).Read()
+			assert.NotNil(t, err)
+		})
+	}
+}
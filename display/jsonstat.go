@@ -0,0 +1,166 @@
+package display
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+// JSONStatVersion is the version of the document written by the json-stat writers.
+// It is incremented on incompatible changes.
+const JSONStatVersion = 1
+
+// JSONStatSchema is the JSON Schema of the document written by the json-stat writers.
+//
+//go:embed jsonstat.schema.json
+var JSONStatSchema string
+
+type jsonStatDocument struct {
+	Version int    `json:"version"`
+	Level   string `json:"level"`
+	// Nodes are the stats by node id.
+	Nodes   interface{}                   `json:"nodes"`
+	Edges   []*jsonStatEdge               `json:"edges"`
+	Metrics map[string]map[string]float64 `json:"metrics"`
+}
+
+type jsonStatEdge struct {
+	Ref    string `json:"ref"`
+	Def    string `json:"def"`
+	Weight int    `json:"weight"`
+}
+
+func jsonStatMetrics(g graph.Graph) map[string]map[string]float64 {
+	var (
+		scores = graphElementMetricScores(g)
+		d      = map[string]map[string]float64{}
+	)
+	for _, id := range g.Nodes() {
+		d[id] = graphElementMetrics(scores, id)
+	}
+	return d
+}
+
+func sortJSONStatEdges(edges []*jsonStatEdge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Ref != edges[j].Ref {
+			return edges[i].Ref < edges[j].Ref
+		}
+		return edges[i].Def < edges[j].Def
+	})
+}
+
+func writeJSONStat(w io.Writer, doc *jsonStatDocument) error {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// NewNodeJSONStatWriter returns a writer that writes a JSON document of the stats of the definitions.
+func NewNodeJSONStatWriter(w io.Writer) Writer {
+	return &nodeJSONStatWriter{
+		w:           w,
+		depCalc:     stat.NewNodeDepCalculator(),
+		statDepCalc: stat.NewNodeStatCalculator(),
+	}
+}
+
+type nodeJSONStatWriter struct {
+	w           io.Writer
+	depCalc     stat.NodeDepCalculator
+	statDepCalc stat.NodeStatCalculator
+}
+
+func (s *nodeJSONStatWriter) Write(node search.Use) error {
+	var (
+		ref = stat.NewNode(node.Ref())
+		def = stat.NewNode(node.Def())
+	)
+	s.depCalc.Add(ref, def)
+	s.statDepCalc.Add(ref, def)
+	return nil
+}
+
+func (s *nodeJSONStatWriter) Flush() error {
+	var (
+		deps  = s.depCalc.Result()
+		edges = make([]*jsonStatEdge, len(deps))
+	)
+	for i, dep := range deps {
+		edges[i] = &jsonStatEdge{
+			Ref:    dep.Ref().ID(),
+			Def:    dep.Def().ID(),
+			Weight: dep.Weight(),
+		}
+	}
+	sortJSONStatEdges(edges)
+	if err := writeJSONStat(s.w, &jsonStatDocument{
+		Version: JSONStatVersion,
+		Level:   "node",
+		Nodes:   s.statDepCalc.Result(),
+		Edges:   edges,
+		Metrics: jsonStatMetrics(stat.NewNodeGraph(deps)),
+	}); err != nil {
+		return fmt.Errorf("NodeJSONStatWriter: %w", err)
+	}
+	return nil
+}
+
+// NewPackageJSONStatWriter returns a writer that writes a JSON document of the stats of the packages.
+func NewPackageJSONStatWriter(w io.Writer) Writer {
+	return &packageJSONStatWriter{
+		w:           w,
+		depCalc:     stat.NewPkgDepCalculator(),
+		statDepCalc: stat.NewPkgStatCalculator(),
+	}
+}
+
+type packageJSONStatWriter struct {
+	w           io.Writer
+	depCalc     stat.PkgDepCalculator
+	statDepCalc stat.PkgStatCalculator
+}
+
+func (s *packageJSONStatWriter) Write(node search.Use) error {
+	var (
+		ref = stat.NewPkg(node.Ref().Pkg())
+		def = stat.NewPkg(node.Def().Pkg())
+	)
+	s.depCalc.Add(ref, def)
+	s.statDepCalc.Add(ref, def)
+	return nil
+}
+
+func (s *packageJSONStatWriter) Flush() error {
+	var (
+		deps  = s.depCalc.Result()
+		edges = make([]*jsonStatEdge, len(deps))
+	)
+	for i, dep := range deps {
+		edges[i] = &jsonStatEdge{
+			Ref:    dep.Ref().ID(),
+			Def:    dep.Def().ID(),
+			Weight: dep.Weight(),
+		}
+	}
+	sortJSONStatEdges(edges)
+	if err := writeJSONStat(s.w, &jsonStatDocument{
+		Version: JSONStatVersion,
+		Level:   "package",
+		Nodes:   s.statDepCalc.Result(),
+		Edges:   edges,
+		Metrics: jsonStatMetrics(stat.NewPkgGraph(deps)),
+	}); err != nil {
+		return fmt.Errorf("PackageJSONStatWriter: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package display
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+// NewLayerWriter returns a writer that reports the layers of the packages.
+func NewLayerWriter(w io.Writer) Writer {
+	return &layerWriter{
+		w:       w,
+		depCalc: stat.NewPkgDepCalculator(),
+	}
+}
+
+type layerWriter struct {
+	w       io.Writer
+	depCalc stat.PkgDepCalculator
+}
+
+func (s *layerWriter) Write(node search.Use) error {
+	s.depCalc.Add(stat.NewPkg(node.Ref().Pkg()), stat.NewPkg(node.Def().Pkg()))
+	return nil
+}
+
+func (s *layerWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("LayerWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *layerWriter) flush() error {
+	l := graph.NewLayering(stat.NewPkgGraph(s.depCalc.Result()))
+	if _, err := fmt.Fprintln(s.w, "# layers"); err != nil {
+		return err
+	}
+	for i, x := range l.Order() {
+		layer, _ := l.Layer(x)
+		if _, err := fmt.Fprintf(s.w, "%d\t%d\t%s\n", i, layer, x); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(s.w, "\n# back edges"); err != nil {
+		return err
+	}
+	for _, e := range l.BackEdges() {
+		if _, err := fmt.Fprintf(s.w, "%s -> %s [%d]\n", e.From(), e.To(), e.Weight()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/logger"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/util"
+)
+
+// The default limits of Mermaid, the larger graphs are not rendered.
+const (
+	mermaidMaxEdges    = 500
+	mermaidMaxTextSize = 50000
+)
+
+// mermaidLabelReplacer escapes the characters that break the quoted labels.
+var mermaidLabelReplacer = strings.NewReplacer(
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+)
+
+func mermaidLabel(v string) string { return `"` + mermaidLabelReplacer.Replace(v) + `"` }
+
+// mermaidEdge writes an edge labeled with the weight.
+func mermaidEdge(b *util.StringBuilder, ref, def string, weight int) {
+	if weight > 1 {
+		b.Writelnf("  %s -->|%d| %s", ref, weight, def)
+		return
+	}
+	b.Writelnf("  %s --> %s", ref, def)
+}
+
+// warnMermaidSize warns if the graph is too large to render.
+func warnMermaidSize(name string, edges int, text string) {
+	if edges > mermaidMaxEdges {
+		logger.Warnf("%s: %d edges exceed %d, Mermaid may not render the graph", name, edges, mermaidMaxEdges)
+	}
+	if len(text) > mermaidMaxTextSize {
+		logger.Warnf("%s: %d characters exceed %d, Mermaid may not render the graph", name, len(text), mermaidMaxTextSize)
+	}
+}
+
+// NewNodeMermaidWriter returns a writer that writes the definitions as a flowchart of Mermaid,
+// the definitions are in the subgraphs of the packages.
+func NewNodeMermaidWriter(w io.Writer) Writer {
+	return &nodeMermaidWriter{
+		w:    w,
+		calc: newNodeElementsCalculator(false),
+	}
+}
+
+type nodeMermaidWriter struct {
+	w    io.Writer
+	calc graphElementsCalculator
+}
+
+func (s *nodeMermaidWriter) Write(node search.Use) error {
+	s.calc.Add(node)
+	return nil
+}
+
+func (s *nodeMermaidWriter) Flush() error {
+	var (
+		elements = s.calc.Result()
+		text     = s.build(elements)
+	)
+	warnMermaidSize("NodeMermaidWriter", len(elements.edges), text)
+	if _, err := fmt.Fprint(s.w, text); err != nil {
+		return fmt.Errorf("NodeMermaidWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *nodeMermaidWriter) build(elements *graphElements) string {
+	var (
+		b        util.StringBuilder
+		nodeIDs  = newSequentialIDs("n")
+		pkgIDs   = newSequentialIDs("p")
+		children = map[string][]*graphElementNode{}
+	)
+	for _, x := range elements.nodes {
+		children[x.parent] = append(children[x.parent], x)
+	}
+	b.Writeln("flowchart LR")
+	for _, pkg := range elements.parents {
+		b.Writelnf("  subgraph %s[%s]", pkgIDs.get(pkg.id), mermaidLabel(pkg.label))
+		for _, x := range children[pkg.id] {
+			b.Writelnf("    %s[%s]", nodeIDs.get(x.id), mermaidLabel(x.label))
+		}
+		b.Writeln("  end")
+	}
+	for _, x := range elements.edges {
+		mermaidEdge(&b, nodeIDs.get(x.source), nodeIDs.get(x.target), x.weight)
+	}
+	return b.String()
+}
+
+// NewPackageMermaidWriter returns a writer that writes the packages as a flowchart of Mermaid.
+func NewPackageMermaidWriter(w io.Writer) Writer {
+	return &packageMermaidWriter{
+		w:    w,
+		calc: newPackageElementsCalculator(false),
+	}
+}
+
+type packageMermaidWriter struct {
+	w    io.Writer
+	calc graphElementsCalculator
+}
+
+func (s *packageMermaidWriter) Write(node search.Use) error {
+	s.calc.Add(node)
+	return nil
+}
+
+func (s *packageMermaidWriter) Flush() error {
+	var (
+		elements = s.calc.Result()
+		text     = s.build(elements)
+	)
+	warnMermaidSize("PackageMermaidWriter", len(elements.edges), text)
+	if _, err := fmt.Fprint(s.w, text); err != nil {
+		return fmt.Errorf("PackageMermaidWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *packageMermaidWriter) build(elements *graphElements) string {
+	var (
+		b      util.StringBuilder
+		pkgIDs = newSequentialIDs("p")
+	)
+	b.Writeln("flowchart LR")
+	for _, x := range elements.nodes {
+		b.Writelnf("  %s[%s]", pkgIDs.get(x.id), mermaidLabel(x.label))
+	}
+	for _, x := range elements.edges {
+		mermaidEdge(&b, pkgIDs.get(x.source), pkgIDs.get(x.target), x.weight)
+	}
+	return b.String()
+}
@@ -2,6 +2,7 @@ package display
 
 import (
 	"fmt"
+	"go/types"
 	"io"
 	"strconv"
 
@@ -15,6 +16,7 @@ type nodeDotWriter struct {
 	w           io.Writer
 	depCalc     stat.NodeDepCalculator
 	statDepCalc stat.NodeStatCalculator
+	closureCalc stat.NodeClosureCalculator
 	conf        *WriterConfig
 }
 
@@ -23,24 +25,39 @@ func NewNodeDotWriter(w io.Writer, opt ...WriterOption) Writer {
 	for _, x := range opt {
 		x(conf)
 	}
-	return &nodeDotWriter{
+	s := &nodeDotWriter{
 		w:           w,
 		depCalc:     stat.NewNodeDepCalculator(),
 		statDepCalc: stat.NewNodeStatCalculator(),
 		conf:        conf,
 	}
+	// the closures are calculated only when displayed
+	if conf.closure {
+		s.closureCalc = stat.NewNodeClosureCalculator()
+	}
+	return s
 }
 
 func (s *nodeDotWriter) Write(node search.Use) error {
 	var (
-		ref = stat.NewNode(node.Ref())
-		def = stat.NewNode(node.Def())
+		ref = s.newNode(node.Ref())
+		def = s.newNode(node.Def())
 	)
 	s.statDepCalc.Add(ref, def)
 	s.depCalc.Add(ref, def)
+	if s.closureCalc != nil {
+		s.closureCalc.Add(ref, def)
+	}
 	return nil
 }
 
+func (s *nodeDotWriter) newNode(node search.Node) stat.Node {
+	if s.conf.foldType {
+		return stat.NewTypeNode(node)
+	}
+	return stat.NewNode(node)
+}
+
 func (s *nodeDotWriter) Flush() error {
 	if _, err := fmt.Fprintln(s.w, s.build().String()); err != nil {
 		return fmt.Errorf("NodeDotWriter: %w", err)
@@ -50,14 +67,17 @@ func (s *nodeDotWriter) Flush() error {
 
 func (s *nodeDotWriter) build() dot.Graph {
 	var (
-		deps       = s.depCalc.Result()
+		deps       = s.foldedDeps(s.depCalc.Result())
 		stats      = s.statDepCalc.Result()
 		pkgStatMap = stat.NewNodeStatPkgMap(stats.Stats())
+		closures   = s.closures()
 
 		subgraphList = dot.NewSubgraphList()
 
-		fontsizeRanking    = s.fontsizeRanking(stats)
+		metricValues       = s.conf.metricValues(stat.NewNodeGraph(deps))
+		fontsizeRanking    = s.fontsizeRanking(stats, metricValues)
 		pkgFontsizeRanking = s.pkgFontsizeRanking(pkgStatMap)
+		clusters           = s.clusters(deps)
 	)
 
 	for _, pkg := range pkgStatMap.PkgList() {
@@ -69,45 +89,57 @@ func (s *nodeDotWriter) build() dot.Graph {
 		for _, st := range statList {
 			pkgWeight += st.Weight()
 			var (
-				fontsize = fontsizeRanking.get(st.Weight())
+				fontsize = fontsizeRanking.get(s.nodeValue(st, metricValues))
 				tooltip  = s.nodeTooltip(st)
-				label    = s.nodeLabel(st)
 				attrList = dot.NewAttrList().
 						Add(dot.NewAttr("color", "white")).
-						Add(dot.NewAttr("style", "filled")).
-						Add(dot.NewAttr("shape", "box")).
-						Add(dot.NewAttr("label", label, dot.WithAttrRaw(true))).
-						Add(dot.NewAttr("tooltip", tooltip)).
-						Add(dot.NewAttr("fontsize", strconv.Itoa(fontsize)))
-				node = dot.NewNode(dot.ID(st.Node().ID()), dot.WithNodeAttrList(attrList))
+						Add(dot.NewAttr("style", "filled"))
 			)
+			if label, ok := s.recordLabel(st); ok {
+				attrList.
+					Add(dot.NewAttr("shape", "record")).
+					Add(dot.NewAttr("label", label))
+			} else {
+				attrList.
+					Add(dot.NewAttr("shape", "box")).
+					Add(dot.NewAttr("label", s.nodeLabel(st, closures), dot.WithAttrRaw(true)))
+			}
+			attrList.
+				Add(dot.NewAttr("tooltip", tooltip)).
+				Add(dot.NewAttr("fontsize", strconv.Itoa(fontsize)))
+			node := dot.NewNode(dot.ID(st.Node().ID()), dot.WithNodeAttrList(attrList))
 			nodeList.Add(node)
 		}
-		fontsize := pkgFontsizeRanking.get(pkgWeight)
-		subgraph := dot.NewSubgraph(
-			dot.ID(pkg.ID()),
-			nodeList,
-			dot.WithSubgraphCluster(true),
-			dot.WithSubgraphAttrList(dot.NewAttrList().
-				Add(dot.NewAttr("color", "lightgrey")).
-				Add(dot.NewAttr("style", "filled")).
-				Add(dot.NewAttr("label", pkg.Pkg().Name())).
-				Add(dot.NewAttr("tooltip", pkg.Pkg().Path())).
-				Add(dot.NewAttr("fontsize", strconv.Itoa(fontsize)))),
+		var (
+			fontsize  = pkgFontsizeRanking.get(pkgWeight)
+			subgraphs = clusters[pkg.ID()]
+			opt       = []dot.SubgraphOption{
+				dot.WithSubgraphCluster(true),
+				dot.WithSubgraphAttrList(dot.NewAttrList().
+					Add(dot.NewAttr("color", "lightgrey")).
+					Add(dot.NewAttr("style", "filled")).
+					Add(dot.NewAttr("label", pkg.Pkg().Name())).
+					Add(dot.NewAttr("tooltip", pkg.Pkg().Path())).
+					Add(dot.NewAttr("fontsize", strconv.Itoa(fontsize)))),
+			}
 		)
-		subgraphList.Add(subgraph)
+		if len(subgraphs) > 1 {
+			opt = append(opt, dot.WithSubgraphSubgraphList(s.clusterSubgraphList(pkg, subgraphs, nodeList)))
+			nodeList = nil
+		}
+		subgraphList.Add(dot.NewSubgraph(dot.ID(pkg.ID()), nodeList, opt...))
 	}
 
 	var (
 		edgeList = dot.NewEdgeList()
 
-		penwidthRanking = s.penwidthRanking(deps)
+		penwidthRanking = s.penwidthRanking(deps, metricValues)
 		weightRanking   = s.weightRanking(deps)
 	)
 
 	for _, dep := range deps {
 		var (
-			penwidth  = penwidthRanking.get(dep.Weight())
+			penwidth  = penwidthRanking.get(s.edgeValue(dep, metricValues))
 			arrowsize = float64(penwidth) / 2
 			weight    = weightRanking.get(dep.Weight())
 			tooltip   = s.edgeTooltip(dep)
@@ -138,6 +170,75 @@ func (s *nodeDotWriter) build() dot.Graph {
 	)
 }
 
+// foldedDeps removes the dependencies in the types if the types are folded.
+func (s *nodeDotWriter) foldedDeps(deps []stat.NodeDep) []stat.NodeDep {
+	if !s.conf.foldType {
+		return deps
+	}
+	r := []stat.NodeDep{}
+	for _, x := range deps {
+		if x.Ref().ID() != x.Def().ID() {
+			r = append(r, x)
+		}
+	}
+	return r
+}
+
+// recordLabel returns the record of the fields and the methods if the node is a folded type.
+func (s *nodeDotWriter) recordLabel(st stat.NodeStat) (string, bool) {
+	if !s.conf.foldType {
+		return "", false
+	}
+	obj, ok := st.Node().Node().Obj().(*types.TypeName)
+	if !ok {
+		return "", false
+	}
+	return newTypeRecord(obj).label(), true
+}
+
+// clusters returns the clusters of the nodes by package id.
+func (s *nodeDotWriter) clusters(deps []stat.NodeDep) map[string][]stat.NodeCluster {
+	d := map[string][]stat.NodeCluster{}
+	if !s.conf.cluster {
+		return d
+	}
+	for _, x := range stat.NewNodeClusters(deps) {
+		id := x.Pkg().ID()
+		d[id] = append(d[id], x)
+	}
+	return d
+}
+
+// clusterSubgraphList distributes the nodes of the package into the subgraphs of the clusters.
+func (*nodeDotWriter) clusterSubgraphList(pkg stat.Pkg, clusters []stat.NodeCluster, nodeList dot.NodeList) dot.SubgraphList {
+	nodes := map[string]dot.Node{}
+	for _, x := range nodeList.Slice() {
+		nodes[x.ID().Raw()] = x
+	}
+	subgraphList := dot.NewSubgraphList()
+	for i, cluster := range clusters {
+		clusterNodeList := dot.NewNodeList()
+		for _, x := range cluster.Nodes() {
+			if node, found := nodes[x.ID()]; found {
+				clusterNodeList.Add(node)
+			}
+		}
+		tooltip := fmt.Sprintf("weight %d, cut weight %d, cohesion %.2f",
+			cluster.Weight(), cluster.CutWeight(), cluster.Cohesion())
+		subgraphList.Add(dot.NewSubgraph(
+			dot.ID(fmt.Sprintf("%s_%d", pkg.ID(), i)),
+			clusterNodeList,
+			dot.WithSubgraphCluster(true),
+			dot.WithSubgraphAttrList(dot.NewAttrList().
+				Add(dot.NewAttr("color", "darkgrey")).
+				Add(dot.NewAttr("style", "dashed")).
+				Add(dot.NewAttr("label", fmt.Sprintf("#%d %.2f", i, cluster.Cohesion()))).
+				Add(dot.NewAttr("tooltip", tooltip))),
+		))
+	}
+	return subgraphList
+}
+
 func (*nodeDotWriter) edgeLabel(dep stat.NodeDep) (string, bool) {
 	if dep.Weight() > 1 {
 		return strconv.Itoa(dep.Weight()), true
@@ -153,27 +254,47 @@ func (s *nodeDotWriter) edgeTooltip(dep stat.NodeDep) string {
 	)
 }
 
-func (s *nodeDotWriter) nodeLabel(st stat.NodeStat) string {
+// closures returns nil if the closures are not displayed.
+func (s *nodeDotWriter) closures() stat.NodeClosureSet {
+	if s.closureCalc == nil {
+		return nil
+	}
+	return s.closureCalc.Result()
+}
+
+func (s *nodeDotWriter) nodeLabel(st stat.NodeStat, closures stat.NodeClosureSet) string {
+	var rows []*nodeLabelRow
+	if closures != nil {
+		if x, ok := closures.Get(st.Node()); ok {
+			rows = closureLabelRows(x.Dependencies(), x.Dependents())
+		}
+	}
 	return fmt.Sprintf("<\n%s\n>",
 		generateNodeLabelHTML(
 			st.Node().Node().Type().String(), s.nodeToLabelTitle(st.Node().Node()),
 			st.Refs().Weight(), st.Defs().Weight(),
 			len(st.Refs().Deps()), len(st.Defs().Deps()),
+			rows...,
 		),
 	)
 }
 
 func (s *nodeDotWriter) nodeToLabelTitle(node search.Node) string { return s.nodeNameWithRecv(node) }
 
-func (*nodeDotWriter) nodeNameWithRecv(node search.Node) string {
+func (*nodeDotWriter) nodeNameWithRecv(node search.Node) string { return nodeNameWithRecv(node) }
+
+func (*nodeDotWriter) nodeToTooltipID(node search.Node) string { return nodeFullName(node) }
+
+func nodeNameWithRecv(node search.Node) string {
 	if recv := node.RecvString(); recv != "" {
 		return fmt.Sprintf("(%s).%s", recv, node.Name())
 	}
 	return node.Name()
 }
 
-func (s *nodeDotWriter) nodeToTooltipID(node search.Node) string {
-	return fmt.Sprintf("%s.%s", node.Pkg().Path(), s.nodeNameWithRecv(node))
+// nodeFullName returns the name qualified by the package path.
+func nodeFullName(node search.Node) string {
+	return fmt.Sprintf("%s.%s", node.Pkg().Path(), nodeNameWithRecv(node))
 }
 
 func (s *nodeDotWriter) nodeToTooltipDetails(node search.Node) string {
@@ -207,10 +328,24 @@ func (s *nodeDotWriter) pkgFontsizeRanking(stats stat.NodeStatPkgMap) *attrRanki
 	return s.conf.newFontsizeRanking(r)
 }
 
-func (s *nodeDotWriter) fontsizeRanking(stats stat.NodeStatSet) *attrRanking {
+func (*nodeDotWriter) nodeValue(st stat.NodeStat, metricValues map[string]int) int {
+	if metricValues != nil {
+		return metricValues[st.Node().ID()]
+	}
+	return st.Weight()
+}
+
+func (*nodeDotWriter) edgeValue(dep stat.NodeDep, metricValues map[string]int) int {
+	if metricValues != nil {
+		return metricValues[dep.Def().ID()]
+	}
+	return dep.Weight()
+}
+
+func (s *nodeDotWriter) fontsizeRanking(stats stat.NodeStatSet, metricValues map[string]int) *attrRanking {
 	r := util.NewRanking()
 	for _, x := range stats.Stats() {
-		r.Add(x.Weight())
+		r.Add(s.nodeValue(x, metricValues))
 	}
 	return s.conf.newFontsizeRanking(r)
 }
@@ -223,10 +358,10 @@ func (s *nodeDotWriter) weightRanking(deps []stat.NodeDep) *attrRanking {
 	return s.conf.newWeightRanking(r)
 }
 
-func (s *nodeDotWriter) penwidthRanking(deps []stat.NodeDep) *attrRanking {
+func (s *nodeDotWriter) penwidthRanking(deps []stat.NodeDep, metricValues map[string]int) *attrRanking {
 	r := util.NewRanking()
 	for _, x := range deps {
-		r.Add(x.Weight())
+		r.Add(s.edgeValue(x, metricValues))
 	}
 	return s.conf.newPenwidthRanking(r)
 }
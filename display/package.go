@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/berquerant/gotypegraph/dot"
+	"github.com/berquerant/gotypegraph/graph"
 	"github.com/berquerant/gotypegraph/search"
 	"github.com/berquerant/gotypegraph/stat"
 	"github.com/berquerant/gotypegraph/util"
@@ -15,6 +16,7 @@ type packageDotWriter struct {
 	w           io.Writer
 	depCalc     stat.PkgDepCalculator
 	statDepCalc stat.PkgStatCalculator
+	closureCalc stat.PkgClosureCalculator
 	conf        *WriterConfig
 }
 
@@ -23,12 +25,17 @@ func NewPackageDotWriter(w io.Writer, opt ...WriterOption) Writer {
 	for _, x := range opt {
 		x(conf)
 	}
-	return &packageDotWriter{
+	s := &packageDotWriter{
 		w:           w,
 		depCalc:     stat.NewPkgDepCalculator(),
 		statDepCalc: stat.NewPkgStatCalculator(),
 		conf:        conf,
 	}
+	// the closures are calculated only when displayed
+	if conf.closure {
+		s.closureCalc = stat.NewPkgClosureCalculator()
+	}
+	return s
 }
 
 func (s *packageDotWriter) Write(node search.Use) error {
@@ -38,6 +45,9 @@ func (s *packageDotWriter) Write(node search.Use) error {
 	)
 	s.depCalc.Add(ref, def)
 	s.statDepCalc.Add(ref, def)
+	if s.closureCalc != nil {
+		s.closureCalc.Add(ref, def)
+	}
 	return nil
 }
 
@@ -50,18 +60,20 @@ func (s *packageDotWriter) Flush() error {
 
 func (s *packageDotWriter) build() dot.Graph {
 	var (
-		deps  = s.depCalc.Result()
-		stats = s.statDepCalc.Result()
+		deps     = s.depCalc.Result()
+		stats    = s.statDepCalc.Result()
+		closures = s.closures()
 
 		nodeList = dot.NewNodeList()
 
-		fontsizeRanking = s.fontsizeRanking(stats)
+		metricValues    = s.conf.metricValues(stat.NewPkgGraph(deps))
+		fontsizeRanking = s.fontsizeRanking(stats, metricValues)
 	)
 
 	for _, stat := range stats.Stats() {
 		var (
-			fontsize = fontsizeRanking.get(stat.Weight())
-			label    = s.nodeLabel(stat)
+			fontsize = fontsizeRanking.get(s.nodeValue(stat, metricValues))
+			label    = s.nodeLabel(stat, closures)
 			tooltip  = s.nodeTooltip(stat)
 			attrList = dot.NewAttrList().
 					Add(dot.NewAttr("shape", "box")).
@@ -76,13 +88,14 @@ func (s *packageDotWriter) build() dot.Graph {
 	var (
 		edgeList = dot.NewEdgeList()
 
-		penwidthRanking = s.penwidthRanking(deps)
+		penwidthRanking = s.penwidthRanking(deps, metricValues)
 		weightRanking   = s.weightRanking(deps)
+		layering        = s.layering(deps)
 	)
 
 	for _, dep := range deps {
 		var (
-			penwidth  = penwidthRanking.get(dep.Weight())
+			penwidth  = penwidthRanking.get(s.edgeValue(dep, metricValues))
 			arrowsize = float64(penwidth) / 2
 			weight    = weightRanking.get(dep.Weight())
 			tooltip   = s.edgeTooltip(dep)
@@ -94,21 +107,75 @@ func (s *packageDotWriter) build() dot.Graph {
 					Add(dot.NewAttr("penwidth", strconv.Itoa(penwidth))).
 					Add(dot.NewAttr("arrowsize", fmt.Sprint(arrowsize))).
 					Add(dot.NewAttr("weight", strconv.Itoa(weight)))
-			edge = dot.NewEdge(
-				dot.ID(dep.Ref().ID()),
-				dot.ID(dep.Def().ID()),
-				dot.WithEdgeAttrList(attrList),
-			)
+		)
+		if layering != nil && s.isBackEdge(layering, dep) {
+			attrList.Add(dot.NewAttr("color", "red"))
+		}
+		edge := dot.NewEdge(
+			dot.ID(dep.Ref().ID()),
+			dot.ID(dep.Def().ID()),
+			dot.WithEdgeAttrList(attrList),
 		)
 		edgeList.Add(edge)
 	}
-	return dot.NewGraph("G", nodeList, edgeList)
+	if layering == nil {
+		return dot.NewGraph("G", nodeList, edgeList)
+	}
+	return dot.NewGraph("G", nodeList, edgeList, dot.WithGraphSubgraphList(s.layerSubgraphList(layering)))
+}
+
+func (s *packageDotWriter) layering(deps []stat.PkgDep) graph.Layering {
+	if !s.conf.layer {
+		return nil
+	}
+	return graph.NewLayering(stat.NewPkgGraph(deps))
+}
+
+// isBackEdge returns true if the dependency is in a cycle.
+func (*packageDotWriter) isBackEdge(layering graph.Layering, dep stat.PkgDep) bool {
+	if dep.Ref().ID() == dep.Def().ID() {
+		return false
+	}
+	l, _ := layering.Layer(dep.Ref().ID())
+	r, _ := layering.Layer(dep.Def().ID())
+	return l <= r
+}
+
+func (*packageDotWriter) layerSubgraphList(layering graph.Layering) dot.SubgraphList {
+	subgraphList := dot.NewSubgraphList()
+	for i, layer := range layering.Layers() {
+		nodeList := dot.NewNodeList()
+		for _, x := range layer {
+			nodeList.Add(dot.NewNode(dot.ID(x)))
+		}
+		subgraphList.Add(dot.NewSubgraph(
+			dot.ID(fmt.Sprintf("layer_%d", i)),
+			nodeList,
+			dot.WithSubgraphAttrList(dot.NewAttrList().
+				Add(dot.NewAttr("rank", "same"))),
+		))
+	}
+	return subgraphList
 }
 
-func (s *packageDotWriter) fontsizeRanking(stats stat.PkgStatSet) *attrRanking {
+func (*packageDotWriter) nodeValue(st stat.PkgStat, metricValues map[string]int) int {
+	if metricValues != nil {
+		return metricValues[st.Pkg().ID()]
+	}
+	return st.Weight()
+}
+
+func (*packageDotWriter) edgeValue(dep stat.PkgDep, metricValues map[string]int) int {
+	if metricValues != nil {
+		return metricValues[dep.Def().ID()]
+	}
+	return dep.Weight()
+}
+
+func (s *packageDotWriter) fontsizeRanking(stats stat.PkgStatSet, metricValues map[string]int) *attrRanking {
 	r := util.NewRanking()
 	for _, x := range stats.Stats() {
-		r.Add(x.Weight())
+		r.Add(s.nodeValue(x, metricValues))
 	}
 	return s.conf.newFontsizeRanking(r)
 }
@@ -121,10 +188,10 @@ func (s *packageDotWriter) weightRanking(deps []stat.PkgDep) *attrRanking {
 	return s.conf.newWeightRanking(r)
 }
 
-func (s *packageDotWriter) penwidthRanking(deps []stat.PkgDep) *attrRanking {
+func (s *packageDotWriter) penwidthRanking(deps []stat.PkgDep, metricValues map[string]int) *attrRanking {
 	r := util.NewRanking()
 	for _, x := range deps {
-		r.Add(x.Weight())
+		r.Add(s.edgeValue(x, metricValues))
 	}
 	return s.conf.newPenwidthRanking(r)
 }
@@ -137,11 +204,26 @@ func (*packageDotWriter) edgeTooltip(dep stat.PkgDep) string {
 	return fmt.Sprintf("%s -> %s [%d]", dep.Ref().Pkg().Path(), dep.Def().Pkg().Path(), dep.Weight())
 }
 
-func (*packageDotWriter) nodeLabel(pkgStat stat.PkgStat) string {
+// closures returns nil if the closures are not displayed.
+func (s *packageDotWriter) closures() stat.PkgClosureSet {
+	if s.closureCalc == nil {
+		return nil
+	}
+	return s.closureCalc.Result()
+}
+
+func (s *packageDotWriter) nodeLabel(pkgStat stat.PkgStat, closures stat.PkgClosureSet) string {
+	var rows []*nodeLabelRow
+	if closures != nil {
+		if x, ok := closures.Get(pkgStat.Pkg()); ok {
+			rows = closureLabelRows(x.Dependencies(), x.Dependents())
+		}
+	}
 	return fmt.Sprintf("<\n%s\n>", generateNodeLabelHTML(
 		"package", pkgStat.Pkg().Pkg().Name(),
 		pkgStat.Refs().Weight(), pkgStat.Defs().Weight(),
 		len(pkgStat.Refs().Deps()), len(pkgStat.Defs().Deps()),
+		rows...,
 	))
 }
 
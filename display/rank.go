@@ -0,0 +1,73 @@
+package display
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+// NewRankWriter returns a writer that reports the top n definitions per metric
+// over the node dependency graph.
+func NewRankWriter(w io.Writer, n int, metrics ...graph.Metric) Writer {
+	if len(metrics) == 0 {
+		metrics = graph.Metrics()
+	}
+	return &rankWriter{
+		w:       w,
+		n:       n,
+		metrics: metrics,
+		depCalc: stat.NewNodeDepCalculator(),
+		nodes:   map[string]stat.Node{},
+	}
+}
+
+type rankWriter struct {
+	w       io.Writer
+	n       int
+	metrics []graph.Metric
+	depCalc stat.NodeDepCalculator
+	nodes   map[string]stat.Node // node id => node
+}
+
+func (s *rankWriter) Write(node search.Use) error {
+	var (
+		ref = stat.NewNode(node.Ref())
+		def = stat.NewNode(node.Def())
+	)
+	s.nodes[ref.ID()] = ref
+	s.nodes[def.ID()] = def
+	s.depCalc.Add(ref, def)
+	return nil
+}
+
+func (s *rankWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("RankWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *rankWriter) flush() error {
+	g := stat.NewNodeGraph(s.depCalc.Result())
+	for i, metric := range s.metrics {
+		if i > 0 {
+			if _, err := fmt.Fprintln(s.w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(s.w, "# %s\n", metric); err != nil {
+			return err
+		}
+		for j, x := range metric.Calculate(g).Top(s.n) {
+			if _, err := fmt.Fprintf(s.w, "%d\t%.6f\t%s\n",
+				j+1, x.Value, nodeFullName(s.nodes[x.ID].Node()),
+			); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package display
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/util"
+)
+
+type (
+	// typeRecord is the fields and the methods of a named type.
+	typeRecord struct {
+		name    string
+		fields  []*typeMember
+		methods []*typeMember
+	}
+
+	typeMember struct {
+		name     string
+		typ      string
+		exported bool
+		embedded bool
+	}
+)
+
+func newTypeRecord(obj *types.TypeName) *typeRecord {
+	var (
+		r = &typeRecord{
+			name: obj.Name(),
+		}
+		qf = types.RelativeTo(obj.Pkg())
+	)
+	if st, ok := obj.Type().Underlying().(*types.Struct); ok {
+		for i := 0; i < st.NumFields(); i++ {
+			f := st.Field(i)
+			r.fields = append(r.fields, &typeMember{
+				name:     f.Name(),
+				typ:      types.TypeString(f.Type(), qf),
+				exported: f.Exported(),
+				embedded: f.Embedded(),
+			})
+		}
+	}
+	addMethod := func(f *types.Func) {
+		r.methods = append(r.methods, &typeMember{
+			name:     f.Name(),
+			typ:      strings.TrimPrefix(types.TypeString(f.Type(), qf), "func"),
+			exported: f.Exported(),
+		})
+	}
+	if iface, ok := obj.Type().Underlying().(*types.Interface); ok {
+		for i := 0; i < iface.NumMethods(); i++ {
+			addMethod(iface.Method(i))
+		}
+	} else if nmd, ok := obj.Type().(*types.Named); ok {
+		for i := 0; i < nmd.NumMethods(); i++ {
+			addMethod(nmd.Method(i))
+		}
+	}
+	return r
+}
+
+// String returns the member in UML notation, e.g. +Name string.
+func (s *typeMember) String() string {
+	visibility := "-"
+	if s.exported {
+		visibility = "+"
+	}
+	if s.embedded {
+		return visibility + s.typ
+	}
+	if strings.HasPrefix(s.typ, "(") {
+		return visibility + s.name + s.typ
+	}
+	return visibility + s.name + " " + s.typ
+}
+
+var recordLabelReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"{", `\{`,
+	"}", `\}`,
+	"|", `\|`,
+	"<", `\<`,
+	">", `\>`,
+)
+
+// label returns the label of the record shape in dot.
+func (s *typeRecord) label() string {
+	var b util.StringBuilder
+	b.Write("{" + recordLabelReplacer.Replace(s.name) + "|")
+	for _, x := range s.fields {
+		b.Write(recordLabelReplacer.Replace(x.String()) + `\l`)
+	}
+	b.Write("|")
+	for _, x := range s.methods {
+		b.Write(recordLabelReplacer.Replace(x.String()) + `\l`)
+	}
+	b.Write("}")
+	return b.String()
+}
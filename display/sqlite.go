@@ -0,0 +1,328 @@
+package display
+
+import (
+	"database/sql"
+	"fmt"
+	"go/token"
+	"sort"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+// sqliteSchema is the schema of the database written by the sqlite writer.
+// The tables are recreated on every write.
+const sqliteSchema = `
+DROP VIEW IF EXISTS package_edges;
+DROP VIEW IF EXISTS definition_stats;
+DROP VIEW IF EXISTS edge_details;
+DROP TABLE IF EXISTS edges;
+DROP TABLE IF EXISTS uses;
+DROP TABLE IF EXISTS definitions;
+DROP TABLE IF EXISTS files;
+DROP TABLE IF EXISTS packages;
+
+CREATE TABLE packages (
+  id INTEGER PRIMARY KEY,
+  path TEXT NOT NULL UNIQUE,
+  name TEXT NOT NULL
+);
+
+CREATE TABLE files (
+  id INTEGER PRIMARY KEY,
+  package_id INTEGER NOT NULL REFERENCES packages(id),
+  path TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE definitions (
+  id INTEGER PRIMARY KEY,
+  key TEXT NOT NULL UNIQUE,
+  package_id INTEGER NOT NULL REFERENCES packages(id),
+  file_id INTEGER REFERENCES files(id),
+  name TEXT NOT NULL,
+  type TEXT NOT NULL,
+  recv TEXT,
+  line INTEGER,
+  col INTEGER
+);
+
+CREATE TABLE uses (
+  id INTEGER PRIMARY KEY,
+  ref_id INTEGER NOT NULL REFERENCES definitions(id),
+  def_id INTEGER NOT NULL REFERENCES definitions(id),
+  file_id INTEGER REFERENCES files(id),
+  line INTEGER,
+  col INTEGER
+);
+
+CREATE TABLE edges (
+  ref_id INTEGER NOT NULL REFERENCES definitions(id),
+  def_id INTEGER NOT NULL REFERENCES definitions(id),
+  weight INTEGER NOT NULL,
+  PRIMARY KEY (ref_id, def_id)
+);
+
+CREATE INDEX files_package_id ON files(package_id);
+CREATE INDEX definitions_package_id ON definitions(package_id);
+CREATE INDEX definitions_file_id ON definitions(file_id);
+CREATE INDEX definitions_name ON definitions(name);
+CREATE INDEX uses_ref_id ON uses(ref_id);
+CREATE INDEX uses_def_id ON uses(def_id);
+CREATE INDEX uses_file_id ON uses(file_id);
+CREATE INDEX edges_def_id ON edges(def_id);
+
+CREATE VIEW edge_details AS
+SELECT
+  rp.path AS ref_package, r.name AS ref_name, r.recv AS ref_recv, r.type AS ref_type,
+  dp.path AS def_package, d.name AS def_name, d.recv AS def_recv, d.type AS def_type,
+  e.weight AS weight
+FROM edges e
+JOIN definitions r ON r.id = e.ref_id
+JOIN definitions d ON d.id = e.def_id
+JOIN packages rp ON rp.id = r.package_id
+JOIN packages dp ON dp.id = d.package_id;
+
+CREATE VIEW package_edges AS
+SELECT
+  rp.path AS ref_package, dp.path AS def_package, SUM(e.weight) AS weight
+FROM edges e
+JOIN definitions r ON r.id = e.ref_id
+JOIN definitions d ON d.id = e.def_id
+JOIN packages rp ON rp.id = r.package_id
+JOIN packages dp ON dp.id = d.package_id
+GROUP BY rp.path, dp.path;
+
+CREATE VIEW definition_stats AS
+SELECT
+  p.path AS package, x.name AS name, x.recv AS recv, x.type AS type,
+  COALESCE((SELECT SUM(weight) FROM edges WHERE ref_id = x.id), 0) AS ref,
+  COALESCE((SELECT SUM(weight) FROM edges WHERE def_id = x.id), 0) AS def,
+  (SELECT COUNT(*) FROM edges WHERE ref_id = x.id) AS uniqref,
+  (SELECT COUNT(*) FROM edges WHERE def_id = x.id) AS uniqdef
+FROM definitions x
+JOIN packages p ON p.id = x.package_id;
+`
+
+// NewSQLiteWriter returns a writer that writes the packages, files, definitions, uses and edges into the database.
+// The database should be SQLite.
+func NewSQLiteWriter(db *sql.DB) Writer {
+	return &sqliteWriter{
+		db: db,
+	}
+}
+
+type sqliteWriter struct {
+	db   *sql.DB
+	uses []search.Use
+}
+
+func (s *sqliteWriter) Write(node search.Use) error {
+	s.uses = append(s.uses, node)
+	return nil
+}
+
+func (s *sqliteWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("SQLiteWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteWriter) flush() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := s.write(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+type (
+	sqliteEdgeKey struct {
+		ref int64
+		def int64
+	}
+
+	// sqliteInserter assigns the ids to the rows.
+	sqliteInserter struct {
+		tx          *sql.Tx
+		packages    map[string]int64
+		files       map[string]int64
+		definitions map[string]int64
+	}
+)
+
+func (s *sqliteWriter) write(tx *sql.Tx) error {
+	if _, err := tx.Exec(sqliteSchema); err != nil {
+		return err
+	}
+	var (
+		ins = &sqliteInserter{
+			tx:          tx,
+			packages:    map[string]int64{},
+			files:       map[string]int64{},
+			definitions: map[string]int64{},
+		}
+		edges = map[sqliteEdgeKey]int{}
+	)
+	for _, use := range s.uses {
+		var (
+			ref  = use.Ref()
+			fset = sqliteFileSet(ref.Pkg())
+		)
+		refID, err := ins.definition(ref, ref.Obj().Pos(), fset)
+		if err != nil {
+			return err
+		}
+		defID, err := ins.definition(use.Def(), use.Def().Obj().Pos(), fset)
+		if err != nil {
+			return err
+		}
+		if err := ins.use(ref, refID, defID, fset); err != nil {
+			return err
+		}
+		edges[sqliteEdgeKey{ref: refID, def: defID}]++
+	}
+
+	keys := make([]sqliteEdgeKey, 0, len(edges))
+	for k := range edges {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ref != keys[j].ref {
+			return keys[i].ref < keys[j].ref
+		}
+		return keys[i].def < keys[j].def
+	})
+	stmt, err := tx.Prepare("INSERT INTO edges (ref_id, def_id, weight) VALUES (?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, k := range keys {
+		if _, err := stmt.Exec(k.ref, k.def, edges[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sqliteFileSet returns the file set of the package, nil if the package is not loaded.
+func sqliteFileSet(pkg search.Pkg) *token.FileSet {
+	if p := pkg.Pkg(); p != nil {
+		return p.Fset
+	}
+	return nil
+}
+
+func sqlitePosition(pos token.Pos, fset *token.FileSet) (token.Position, bool) {
+	if fset == nil || !pos.IsValid() {
+		return token.Position{}, false
+	}
+	return fset.Position(pos), true
+}
+
+func sqliteNullString(s string) sql.NullString {
+	return sql.NullString{
+		String: s,
+		Valid:  s != "",
+	}
+}
+
+func (s *sqliteInserter) insert(query string, args ...interface{}) (int64, error) {
+	r, err := s.tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return r.LastInsertId()
+}
+
+func (s *sqliteInserter) pkg(pkg search.Pkg) (int64, error) {
+	path := stat.NewPkg(pkg).ID()
+	if id, ok := s.packages[path]; ok {
+		return id, nil
+	}
+	id, err := s.insert("INSERT INTO packages (path, name) VALUES (?, ?)", path, pkg.Name())
+	if err != nil {
+		return 0, err
+	}
+	s.packages[path] = id
+	return id, nil
+}
+
+// file returns the id of the file, invalid if the file is unknown.
+func (s *sqliteInserter) file(pkg search.Pkg, pos token.Pos, fset *token.FileSet) (sql.NullInt64, token.Position, error) {
+	if p := sqliteFileSet(pkg); p != nil {
+		fset = p
+	}
+	position, ok := sqlitePosition(pos, fset)
+	if !ok || position.Filename == "" {
+		return sql.NullInt64{}, position, nil
+	}
+	if id, ok := s.files[position.Filename]; ok {
+		return sql.NullInt64{Int64: id, Valid: true}, position, nil
+	}
+	pkgID, err := s.pkg(pkg)
+	if err != nil {
+		return sql.NullInt64{}, position, err
+	}
+	id, err := s.insert("INSERT INTO files (package_id, path) VALUES (?, ?)", pkgID, position.Filename)
+	if err != nil {
+		return sql.NullInt64{}, position, err
+	}
+	s.files[position.Filename] = id
+	return sql.NullInt64{Int64: id, Valid: true}, position, nil
+}
+
+func sqliteLineCol(fileID sql.NullInt64, position token.Position) (sql.NullInt64, sql.NullInt64) {
+	if !fileID.Valid {
+		return sql.NullInt64{}, sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(position.Line), Valid: true},
+		sql.NullInt64{Int64: int64(position.Column), Valid: true}
+}
+
+func (s *sqliteInserter) definition(node search.Node, pos token.Pos, fset *token.FileSet) (int64, error) {
+	key := stat.NewNode(node).ID()
+	if id, ok := s.definitions[key]; ok {
+		return id, nil
+	}
+	pkgID, err := s.pkg(node.Pkg())
+	if err != nil {
+		return 0, err
+	}
+	fileID, position, err := s.file(node.Pkg(), pos, fset)
+	if err != nil {
+		return 0, err
+	}
+	line, col := sqliteLineCol(fileID, position)
+	id, err := s.insert(
+		"INSERT INTO definitions (key, package_id, file_id, name, type, recv, line, col) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		key, pkgID, fileID, node.Name(), node.Type().String(), sqliteNullString(node.RecvString()), line, col,
+	)
+	if err != nil {
+		return 0, err
+	}
+	s.definitions[key] = id
+	return id, nil
+}
+
+func (s *sqliteInserter) use(ref search.RefNode, refID, defID int64, fset *token.FileSet) error {
+	var pos token.Pos
+	if ident := ref.Ident(); ident != nil {
+		pos = ident.Pos()
+	}
+	fileID, position, err := s.file(ref.Pkg(), pos, fset)
+	if err != nil {
+		return err
+	}
+	line, col := sqliteLineCol(fileID, position)
+	_, err = s.insert(
+		"INSERT INTO uses (ref_id, def_id, file_id, line, col) VALUES (?, ?, ?, ?, ?)",
+		refID, defID, fileID, line, col,
+	)
+	return err
+}
@@ -0,0 +1,134 @@
+package display
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/berquerant/gotypegraph/search"
+)
+
+// NewNodeTableWriter returns a writer that writes the edge list and the node table of the definitions.
+// comma is the field delimiter, e.g. ',' for CSV and '\t' for TSV.
+func NewNodeTableWriter(edges, nodes io.Writer, comma rune) Writer {
+	return &tableWriter{
+		edges: edges,
+		nodes: nodes,
+		comma: comma,
+		calc:  newNodeElementsCalculator(true),
+	}
+}
+
+// NewPackageTableWriter returns a writer that writes the edge list and the node table of the packages.
+func NewPackageTableWriter(edges, nodes io.Writer, comma rune) Writer {
+	return &tableWriter{
+		edges:   edges,
+		nodes:   nodes,
+		comma:   comma,
+		calc:    newPackageElementsCalculator(true),
+		pkgOnly: true,
+	}
+}
+
+type tableWriter struct {
+	edges   io.Writer
+	nodes   io.Writer
+	comma   rune
+	calc    graphElementsCalculator
+	pkgOnly bool
+}
+
+func (s *tableWriter) Write(node search.Use) error {
+	s.calc.Add(node)
+	return nil
+}
+
+func (s *tableWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("TableWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *tableWriter) newCSVWriter(w io.Writer) *csv.Writer {
+	x := csv.NewWriter(w)
+	x.Comma = s.comma
+	return x
+}
+
+func (s *tableWriter) flush() error {
+	elements := s.calc.Result()
+	if err := s.writeEdges(elements); err != nil {
+		return err
+	}
+	return s.writeNodes(elements)
+}
+
+func (s *tableWriter) writeEdges(elements *graphElements) error {
+	w := s.newCSVWriter(s.edges)
+	if s.pkgOnly {
+		_ = w.Write([]string{"ref", "def", "weight"})
+		for _, x := range elements.edges {
+			_ = w.Write([]string{x.source, x.target, strconv.Itoa(x.weight)})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	nodes := make(map[string]*graphElementNode, len(elements.nodes))
+	for _, x := range elements.nodes {
+		nodes[x.id] = x
+	}
+	_ = w.Write([]string{
+		"ref", "def", "ref_type", "def_type", "weight",
+		"ref_package", "def_package", "ref_position", "def_position",
+	})
+	for _, x := range elements.edges {
+		ref, def := nodes[x.source], nodes[x.target]
+		_ = w.Write([]string{
+			x.source, x.target, ref.typ, def.typ, strconv.Itoa(x.weight),
+			ref.pkg, def.pkg, ref.position, def.position,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func (s *tableWriter) writeNodes(elements *graphElements) error {
+	var (
+		w       = s.newCSVWriter(s.nodes)
+		metrics = graph.Metrics()
+		header  []string
+	)
+	if s.pkgOnly {
+		header = []string{"id", "name"}
+	} else {
+		header = []string{"id", "package", "name", "type", "recv", "position"}
+	}
+	header = append(header, "ref", "def", "uniqref", "uniqdef")
+	for _, m := range metrics {
+		header = append(header, m.String())
+	}
+	_ = w.Write(header)
+
+	for _, x := range elements.nodes {
+		var row []string
+		if s.pkgOnly {
+			row = []string{x.id, x.name}
+		} else {
+			row = []string{x.id, x.pkg, x.name, x.typ, x.recv, x.position}
+		}
+		row = append(row,
+			strconv.Itoa(x.ref), strconv.Itoa(x.def),
+			strconv.Itoa(x.uniqRef), strconv.Itoa(x.uniqDef),
+		)
+		for _, m := range metrics {
+			row = append(row, strconv.FormatFloat(x.metrics[m.String()], 'g', -1, 64))
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+	return w.Error()
+}
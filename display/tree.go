@@ -0,0 +1,256 @@
+package display
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+type (
+	TreeConfig struct {
+		root    *regexp.Regexp
+		reverse bool
+		depth   int
+	}
+
+	TreeOption func(*TreeConfig)
+)
+
+// WithTreeRoot selects the roots whose full names match the regexp.
+// The roots are the nodes that no one uses (or use nothing if reverse) by default.
+func WithTreeRoot(v *regexp.Regexp) TreeOption {
+	return func(c *TreeConfig) {
+		c.root = v
+	}
+}
+
+// WithTreeReverse writes the trees of the dependents instead of the dependencies.
+func WithTreeReverse(v bool) TreeOption {
+	return func(c *TreeConfig) {
+		c.reverse = v
+	}
+}
+
+// WithTreeDepth limits the depth of the trees, 0 means no limit.
+func WithTreeDepth(v int) TreeOption {
+	return func(c *TreeConfig) {
+		c.depth = v
+	}
+}
+
+// NewNodeTreeWriter returns a writer that writes the indented trees of the definitions
+// that each definition uses, with the weights.
+// The nodes in the cycles are marked with (cycle),
+// the nodes already expanded are marked with (*) and not expanded again.
+func NewNodeTreeWriter(w io.Writer, opt ...TreeOption) Writer {
+	return &treeWriter{
+		w:     w,
+		conf:  newTreeConfig(opt...),
+		calc:  stat.NewNodeDepCalculator(),
+		nodes: map[string]search.Node{},
+	}
+}
+
+// NewPackageTreeWriter returns a writer that writes the indented trees of the packages.
+func NewPackageTreeWriter(w io.Writer, opt ...TreeOption) Writer {
+	return &pkgTreeWriter{
+		w:    w,
+		conf: newTreeConfig(opt...),
+		calc: stat.NewPkgDepCalculator(),
+	}
+}
+
+func newTreeConfig(opt ...TreeOption) *TreeConfig {
+	var c TreeConfig
+	for _, x := range opt {
+		x(&c)
+	}
+	return &c
+}
+
+type treeWriter struct {
+	w     io.Writer
+	conf  *TreeConfig
+	calc  stat.NodeDepCalculator
+	nodes map[string]search.Node
+}
+
+func (s *treeWriter) Write(node search.Use) error {
+	var (
+		ref = stat.NewNode(node.Ref())
+		def = stat.NewNode(node.Def())
+	)
+	s.calc.Add(ref, def)
+	s.nodes[ref.ID()] = ref.Node()
+	s.nodes[def.ID()] = def.Node()
+	return nil
+}
+
+func (s *treeWriter) Flush() error {
+	labels := make(map[string]string, len(s.nodes))
+	for id, node := range s.nodes {
+		labels[id] = nodeFullName(node)
+	}
+	if err := newTreePrinter(s.conf, stat.NewNodeGraph(s.calc.Result()), labels).print(s.w); err != nil {
+		return fmt.Errorf("TreeWriter: %w", err)
+	}
+	return nil
+}
+
+type pkgTreeWriter struct {
+	w    io.Writer
+	conf *TreeConfig
+	calc stat.PkgDepCalculator
+}
+
+func (s *pkgTreeWriter) Write(node search.Use) error {
+	s.calc.Add(stat.NewPkg(node.Ref().Pkg()), stat.NewPkg(node.Def().Pkg()))
+	return nil
+}
+
+func (s *pkgTreeWriter) Flush() error {
+	g := stat.NewPkgGraph(s.calc.Result())
+	labels := map[string]string{}
+	for _, x := range g.Nodes() {
+		labels[x] = x
+	}
+	if err := newTreePrinter(s.conf, g, labels).print(s.w); err != nil {
+		return fmt.Errorf("PkgTreeWriter: %w", err)
+	}
+	return nil
+}
+
+type treePrinter struct {
+	conf   *TreeConfig
+	g      graph.Graph
+	labels map[string]string
+	// expanded are the nodes whose children are already written.
+	expanded map[string]bool
+	// path are the nodes from the root to the current node.
+	path map[string]bool
+	w    *bufio.Writer
+}
+
+func newTreePrinter(conf *TreeConfig, g graph.Graph, labels map[string]string) *treePrinter {
+	return &treePrinter{
+		conf:     conf,
+		g:        g,
+		labels:   labels,
+		expanded: map[string]bool{},
+		path:     map[string]bool{},
+	}
+}
+
+func (s *treePrinter) label(id string) string {
+	if x, ok := s.labels[id]; ok {
+		return x
+	}
+	return id
+}
+
+type treeChild struct {
+	id     string
+	weight int
+}
+
+// children returns the used nodes, or the users if reverse, sorted by label.
+func (s *treePrinter) children(id string) []*treeChild {
+	var xs []*treeChild
+	if s.conf.reverse {
+		for _, e := range s.g.In(id) {
+			xs = append(xs, &treeChild{id: e.From(), weight: e.Weight()})
+		}
+	} else {
+		for _, e := range s.g.Out(id) {
+			xs = append(xs, &treeChild{id: e.To(), weight: e.Weight()})
+		}
+	}
+	sort.Slice(xs, func(i, j int) bool { return s.label(xs[i].id) < s.label(xs[j].id) })
+	return xs
+}
+
+// roots returns the selected roots or the first nodes of the strongly connected components
+// that no other components point to.
+func (s *treePrinter) roots() []string {
+	var roots []string
+	if s.conf.root != nil {
+		for _, x := range s.g.Nodes() {
+			if s.conf.root.MatchString(s.label(x)) {
+				roots = append(roots, x)
+			}
+		}
+	} else {
+		var (
+			components = graph.StronglyConnectedComponents(s.g)
+			compOf     = map[string]int{}
+		)
+		for i, c := range components {
+			for _, x := range c {
+				compOf[x] = i
+			}
+		}
+		pointed := make([]bool, len(components))
+		for _, e := range s.g.Edges() {
+			from, to := compOf[e.From()], compOf[e.To()]
+			if s.conf.reverse {
+				from, to = to, from
+			}
+			if from != to {
+				pointed[to] = true
+			}
+		}
+		for i, c := range components {
+			if !pointed[i] {
+				roots = append(roots, c[0])
+			}
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool { return s.label(roots[i]) < s.label(roots[j]) })
+	return roots
+}
+
+func (s *treePrinter) print(w io.Writer) error {
+	s.w = bufio.NewWriter(w)
+	for _, root := range s.roots() {
+		s.w.WriteString(s.label(root))
+		s.printNode(root, "", 0)
+	}
+	return s.w.Flush()
+}
+
+// printNode writes the rest of the line of the node and its children.
+func (s *treePrinter) printNode(id, prefix string, depth int) {
+	children := s.children(id)
+	switch {
+	case len(children) == 0:
+		s.w.WriteString("\n")
+		return
+	case s.path[id]:
+		s.w.WriteString(" (cycle)\n")
+		return
+	case s.expanded[id]:
+		s.w.WriteString(" (*)\n")
+		return
+	case s.conf.depth > 0 && depth >= s.conf.depth:
+		s.w.WriteString(" ...\n")
+		return
+	}
+	s.w.WriteString("\n")
+	s.expanded[id] = true
+	s.path[id] = true
+	for i, c := range children {
+		branch, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(s.w, "%s%s%s [%d]", prefix, branch, s.label(c.id), c.weight)
+		s.printNode(c.id, prefix+indent, depth+1)
+	}
+	s.path[id] = false
+}
@@ -0,0 +1,123 @@
+package display_test
+
+import (
+	"bytes"
+	"go/token"
+	"regexp"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeWriter(t *testing.T) {
+	var (
+		fset = token.NewFileSet()
+		a    = newTestPkg(fset, "a")
+		b    = newTestPkg(fset, "b")
+		c    = newTestPkg(fset, "c")
+		d    = newTestPkg(fset, "d")
+
+		fa = testObj{pkg: a, obj: a.newFunc("FA", "a.go", 1)}
+		ga = testObj{pkg: a, obj: a.newFunc("GA", "a.go", 2)}
+		fb = testObj{pkg: b, obj: b.newFunc("FB", "b.go", 1)}
+		fc = testObj{pkg: c, obj: c.newFunc("FC", "c.go", 1)}
+		fd = testObj{pkg: d, obj: d.newFunc("FD", "d.go", 1)}
+
+		// a -> a, a -> b <-> c, a -> d -> c
+		uses = []search.Use{
+			newTestUse(fa, a.pos("a.go", 3), ga),
+			newTestUse(fa, a.pos("a.go", 4), fb),
+			newTestUse(fa, a.pos("a.go", 5), fb),
+			newTestUse(fa, a.pos("a.go", 6), fd),
+			newTestUse(fb, b.pos("b.go", 2), fc),
+			newTestUse(fc, c.pos("c.go", 2), fb),
+			newTestUse(fd, d.pos("d.go", 2), fc),
+		}
+	)
+
+	for _, tc := range []struct {
+		title string
+		opt   []display.TreeOption
+		want  string
+	}{
+		{
+			title: "roots",
+			want: `a
+├── a [1] (cycle)
+├── b [2]
+│   └── c [1]
+│       └── b [1] (cycle)
+└── d [1]
+    └── c [1] (*)
+`,
+		},
+		{
+			title: "depth",
+			opt: []display.TreeOption{
+				display.WithTreeDepth(1),
+			},
+			want: `a
+├── a [1] (cycle)
+├── b [2] ...
+└── d [1] ...
+`,
+		},
+		{
+			title: "reverse",
+			opt: []display.TreeOption{
+				display.WithTreeReverse(true),
+			},
+			want: `b
+├── a [2]
+│   └── a [1] (cycle)
+└── c [1]
+    ├── b [1] (cycle)
+    └── d [1]
+        └── a [1] (*)
+`,
+		},
+		{
+			title: "select roots",
+			opt: []display.TreeOption{
+				display.WithTreeRoot(regexp.MustCompile(`^[cd]$`)),
+			},
+			want: `c
+└── b [1]
+    └── c [1] (cycle)
+d
+└── c [1] (*)
+`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			var (
+				buf bytes.Buffer
+				w   = display.NewPackageTreeWriter(&buf, tc.opt...)
+			)
+			for _, x := range uses {
+				assert.Nil(t, w.Write(x))
+			}
+			assert.Nil(t, w.Flush())
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+
+	t.Run("node", func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+			w   = display.NewNodeTreeWriter(&buf)
+		)
+		for _, x := range uses[:4] {
+			assert.Nil(t, w.Write(x))
+		}
+		assert.Nil(t, w.Flush())
+		assert.Equal(t, `a.FA
+├── a.GA [1]
+├── b.FB [2]
+└── d.FD [1]
+`, buf.String())
+	})
+}
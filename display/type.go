@@ -0,0 +1,57 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+// NewTypeJSONWriter returns a writer that writes the nodes and the dependencies between them as a JSON
+// after merging the methods and the fields into the nodes of their receiver types.
+func NewTypeJSONWriter(w io.Writer) Writer {
+	return &typeJSONWriter{
+		w:        w,
+		depCalc:  stat.NewNodeDepCalculator(),
+		statCalc: stat.NewNodeStatCalculator(),
+	}
+}
+
+type typeJSONWriter struct {
+	w        io.Writer
+	depCalc  stat.NodeDepCalculator
+	statCalc stat.NodeStatCalculator
+}
+
+func (s *typeJSONWriter) Write(node search.Use) error {
+	var (
+		ref = stat.NewTypeNode(node.Ref())
+		def = stat.NewTypeNode(node.Def())
+	)
+	s.depCalc.Add(ref, def)
+	s.statCalc.Add(ref, def)
+	return nil
+}
+
+func (s *typeJSONWriter) Flush() error {
+	if err := s.flush(); err != nil {
+		return fmt.Errorf("TypeJSONWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *typeJSONWriter) flush() error {
+	deps := s.depCalc.Result()
+	sortNodeDeps(deps)
+	b, err := json.Marshal(map[string]interface{}{
+		"nodes": s.statCalc.Result(),
+		"deps":  deps,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.w, string(b))
+	return err
+}
@@ -0,0 +1,404 @@
+package display
+
+import (
+	"fmt"
+	"go/types"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/berquerant/gotypegraph/util"
+)
+
+/* class diagram model */
+
+type (
+	umlType struct {
+		id     string
+		obj    *types.TypeName
+		record *typeRecord
+	}
+
+	umlPkg struct {
+		path  string
+		types []*umlType
+	}
+
+	umlRelationKind int
+
+	umlRelation struct {
+		from *umlType
+		to   *umlType
+		kind umlRelationKind
+	}
+)
+
+const (
+	// umlInheritance is an embedding.
+	umlInheritance umlRelationKind = iota
+	// umlRealization is an implementation of an interface.
+	umlRealization
+	// umlAssociation is a field whose type refers to the other type.
+	umlAssociation
+	// umlDependency is the other uses.
+	umlDependency
+)
+
+func (s *umlType) isInterface() bool {
+	_, ok := s.obj.Type().Underlying().(*types.Interface)
+	return ok
+}
+
+func (s *umlType) pkgPath() string {
+	if pkg := s.obj.Pkg(); pkg != nil {
+		return pkg.Path()
+	}
+	return ""
+}
+
+func umlTypeKey(obj *types.TypeName) string {
+	if pkg := obj.Pkg(); pkg != nil {
+		return pkg.Path() + "." + obj.Name()
+	}
+	return obj.Name()
+}
+
+// umlCollector collects the named types from the uses.
+type umlCollector struct {
+	types map[string]*umlType
+	uses  map[string]map[string]bool
+}
+
+func newUMLCollector() *umlCollector {
+	return &umlCollector{
+		types: map[string]*umlType{},
+		uses:  map[string]map[string]bool{},
+	}
+}
+
+func (s *umlCollector) add(node search.Node) (string, bool) {
+	obj, ok := stat.NewTypeNode(node).Node().Obj().(*types.TypeName)
+	if !ok {
+		return "", false
+	}
+	key := umlTypeKey(obj)
+	if _, found := s.types[key]; !found {
+		s.types[key] = &umlType{
+			id:     groupNodeIDReplacer.ReplaceAllString(key, "_"),
+			obj:    obj,
+			record: newTypeRecord(obj),
+		}
+	}
+	return key, true
+}
+
+func (s *umlCollector) write(node search.Use) {
+	ref, refOK := s.add(node.Ref())
+	def, defOK := s.add(node.Def())
+	if !refOK || !defOK || ref == def {
+		return
+	}
+	if _, ok := s.uses[ref]; !ok {
+		s.uses[ref] = map[string]bool{}
+	}
+	s.uses[ref][def] = true
+}
+
+// pkgs returns the types grouped by package, sorted by path and name.
+func (s *umlCollector) pkgs() []*umlPkg {
+	d := map[string]*umlPkg{}
+	for _, key := range s.sortedKeys() {
+		t := s.types[key]
+		p, ok := d[t.pkgPath()]
+		if !ok {
+			p = &umlPkg{
+				path: t.pkgPath(),
+			}
+			d[t.pkgPath()] = p
+		}
+		p.types = append(p.types, t)
+	}
+	r := make([]*umlPkg, 0, len(d))
+	for _, x := range d {
+		r = append(r, x)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].path < r[j].path })
+	return r
+}
+
+func (s *umlCollector) sortedKeys() []string {
+	keys := make([]string, 0, len(s.types))
+	for k := range s.types {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// relations returns the relations between the types.
+// A pair of the types has the first relation of inheritance, realization, association and dependency.
+func (s *umlCollector) relations() []*umlRelation {
+	var (
+		r    []*umlRelation
+		seen = map[string]bool{}
+		keys = s.sortedKeys()
+	)
+	add := func(from, to *umlType, kind umlRelationKind) {
+		id := from.id + ">" + to.id
+		if from == to || seen[id] {
+			return
+		}
+		seen[id] = true
+		r = append(r, &umlRelation{
+			from: from,
+			to:   to,
+			kind: kind,
+		})
+	}
+	lookup := func(t types.Type) (*umlType, bool) {
+		if nmd, ok := t.(*types.Named); ok {
+			x, found := s.types[umlTypeKey(nmd.Obj())]
+			return x, found
+		}
+		return nil, false
+	}
+
+	for _, key := range keys {
+		t := s.types[key]
+		switch u := t.obj.Type().Underlying().(type) {
+		case *types.Struct:
+			for i := 0; i < u.NumFields(); i++ {
+				if f := u.Field(i); f.Embedded() {
+					if x, ok := lookup(derefType(f.Type())); ok {
+						add(t, x, umlInheritance)
+					}
+				}
+			}
+		case *types.Interface:
+			for i := 0; i < u.NumEmbeddeds(); i++ {
+				if x, ok := lookup(u.EmbeddedType(i)); ok {
+					add(t, x, umlInheritance)
+				}
+			}
+		}
+	}
+	for _, key := range keys {
+		t := s.types[key]
+		if t.isInterface() {
+			continue
+		}
+		for _, k := range keys {
+			x := s.types[k]
+			iface, ok := x.obj.Type().Underlying().(*types.Interface)
+			if !ok || iface.NumMethods() == 0 {
+				continue
+			}
+			if types.Implements(t.obj.Type(), iface) || types.Implements(types.NewPointer(t.obj.Type()), iface) {
+				add(t, x, umlRealization)
+			}
+		}
+	}
+	for _, key := range keys {
+		t := s.types[key]
+		if u, ok := t.obj.Type().Underlying().(*types.Struct); ok {
+			for i := 0; i < u.NumFields(); i++ {
+				if f := u.Field(i); !f.Embedded() {
+					walkNamedTypes(f.Type(), func(nmd *types.Named) {
+						if x, ok := lookup(nmd); ok {
+							add(t, x, umlAssociation)
+						}
+					})
+				}
+			}
+		}
+	}
+	for _, key := range keys {
+		defs := make([]string, 0, len(s.uses[key]))
+		for k := range s.uses[key] {
+			defs = append(defs, k)
+		}
+		sort.Strings(defs)
+		for _, k := range defs {
+			add(s.types[key], s.types[k], umlDependency)
+		}
+	}
+	return r
+}
+
+func derefType(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+// walkNamedTypes calls f with the named types that compose t.
+func walkNamedTypes(t types.Type, f func(*types.Named)) {
+	switch t := t.(type) {
+	case *types.Named:
+		f(t)
+	case *types.Pointer:
+		walkNamedTypes(t.Elem(), f)
+	case *types.Slice:
+		walkNamedTypes(t.Elem(), f)
+	case *types.Array:
+		walkNamedTypes(t.Elem(), f)
+	case *types.Chan:
+		walkNamedTypes(t.Elem(), f)
+	case *types.Map:
+		walkNamedTypes(t.Key(), f)
+		walkNamedTypes(t.Elem(), f)
+	}
+}
+
+// umlArrow returns the arrow from the relation, the same in PlantUML and Mermaid.
+func umlArrow(r *umlRelation) string {
+	switch r.kind {
+	case umlInheritance:
+		return fmt.Sprintf("%s <|-- %s", r.to.id, r.from.id)
+	case umlRealization:
+		return fmt.Sprintf("%s <|.. %s", r.to.id, r.from.id)
+	case umlAssociation:
+		return fmt.Sprintf("%s --> %s", r.from.id, r.to.id)
+	default:
+		return fmt.Sprintf("%s ..> %s", r.from.id, r.to.id)
+	}
+}
+
+/* PlantUML */
+
+// NewPlantUMLWriter returns a writer that writes the named types as a class diagram of PlantUML.
+// The structs have the fields, the interfaces have the methods,
+// the embeddings are the inheritances, the implementations are the realizations,
+// the fields are the associations and the other uses are the dependencies.
+func NewPlantUMLWriter(w io.Writer) Writer {
+	return &plantUMLWriter{
+		w:         w,
+		collector: newUMLCollector(),
+	}
+}
+
+type plantUMLWriter struct {
+	w         io.Writer
+	collector *umlCollector
+}
+
+func (s *plantUMLWriter) Write(node search.Use) error {
+	s.collector.write(node)
+	return nil
+}
+
+func (s *plantUMLWriter) Flush() error {
+	if _, err := fmt.Fprint(s.w, s.build()); err != nil {
+		return fmt.Errorf("PlantUMLWriter: %w", err)
+	}
+	return nil
+}
+
+func (s *plantUMLWriter) build() string {
+	var b util.StringBuilder
+	b.Writeln("@startuml")
+	for _, pkg := range s.collector.pkgs() {
+		indent := ""
+		if pkg.path != "" {
+			b.Writelnf(`package "%s" {`, pkg.path)
+			indent = "  "
+		}
+		for _, t := range pkg.types {
+			kind := "class"
+			if t.isInterface() {
+				kind = "interface"
+			}
+			b.Writelnf(`%s%s "%s" as %s {`, indent, kind, t.record.name, t.id)
+			for _, x := range t.record.fields {
+				// parentheses make a member a method
+				if strings.Contains(x.String(), "(") {
+					b.Writelnf("%s  {field} %s", indent, x)
+					continue
+				}
+				b.Writelnf("%s  %s", indent, x)
+			}
+			for _, x := range t.record.methods {
+				b.Writelnf("%s  %s", indent, x)
+			}
+			b.Writelnf("%s}", indent)
+		}
+		if pkg.path != "" {
+			b.Writeln("}")
+		}
+	}
+	for _, r := range s.collector.relations() {
+		b.Writeln(umlArrow(r))
+	}
+	b.Writeln("@enduml")
+	return b.String()
+}
+
+/* Mermaid */
+
+// NewMermaidClassWriter returns a writer that writes the named types as a classDiagram of Mermaid.
+// The relations are the same as NewPlantUMLWriter.
+func NewMermaidClassWriter(w io.Writer) Writer {
+	return &mermaidClassWriter{
+		w:         w,
+		collector: newUMLCollector(),
+	}
+}
+
+type mermaidClassWriter struct {
+	w         io.Writer
+	collector *umlCollector
+}
+
+func (s *mermaidClassWriter) Write(node search.Use) error {
+	s.collector.write(node)
+	return nil
+}
+
+func (s *mermaidClassWriter) Flush() error {
+	if _, err := fmt.Fprint(s.w, s.build()); err != nil {
+		return fmt.Errorf("MermaidClassWriter: %w", err)
+	}
+	return nil
+}
+
+var (
+	// mermaidClassReplacer escapes the braces that close the class body.
+	mermaidClassReplacer = strings.NewReplacer("{", "#123;", "}", "#125;")
+	// mermaidClassFieldReplacer also escapes the parentheses that make a member a method.
+	mermaidClassFieldReplacer = strings.NewReplacer("{", "#123;", "}", "#125;", "(", "#40;", ")", "#41;")
+)
+
+func (s *mermaidClassWriter) build() string {
+	var b util.StringBuilder
+	b.Writeln("classDiagram")
+	for _, pkg := range s.collector.pkgs() {
+		indent := "  "
+		if pkg.path != "" {
+			b.Writelnf("  namespace %s {", groupNodeIDReplacer.ReplaceAllString(pkg.path, "_"))
+			indent = "    "
+		}
+		for _, t := range pkg.types {
+			b.Writelnf(`%sclass %s["%s"] {`, indent, t.id, t.record.name)
+			if t.isInterface() {
+				b.Writelnf("%s  <<interface>>", indent)
+			}
+			for _, x := range t.record.fields {
+				b.Writelnf("%s  %s", indent, mermaidClassFieldReplacer.Replace(x.String()))
+			}
+			for _, x := range t.record.methods {
+				b.Writelnf("%s  %s", indent, mermaidClassReplacer.Replace(x.String()))
+			}
+			b.Writelnf("%s}", indent)
+		}
+		if pkg.path != "" {
+			b.Writeln("  }")
+		}
+	}
+	for _, r := range s.collector.relations() {
+		b.Writelnf("  %s", umlArrow(r))
+	}
+	return b.String()
+}
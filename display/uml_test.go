@@ -0,0 +1,69 @@
+package display_test
+
+import (
+	"bytes"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassWriter(t *testing.T) {
+	var (
+		fset = token.NewFileSet()
+		a    = newTestPkg(fset, "example.com/a")
+
+		errorType = types.Universe.Lookup("error").Type()
+		callback  = types.NewField(a.pos("a.go", 2), a.types, "Callback", types.NewSignature(
+			nil,
+			types.NewTuple(types.NewVar(token.NoPos, a.types, "", types.Typ[types.Int])),
+			types.NewTuple(types.NewVar(token.NoPos, a.types, "", errorType)),
+			false,
+		), false)
+		count  = types.NewField(a.pos("a.go", 3), a.types, "count", types.Typ[types.Int], false)
+		tName  = types.NewTypeName(a.pos("a.go", 1), a.types, "T", nil)
+		_      = types.NewNamed(tName, types.NewStruct([]*types.Var{callback, count}, nil), nil)
+		f      = testObj{pkg: a, obj: a.newFunc("F", "a.go", 5)}
+		tDef   = testObj{pkg: a, obj: tName}
+		useOfT = newTestUse(f, a.pos("a.go", 6), tDef)
+	)
+
+	for _, tc := range []struct {
+		title string
+		newW  func(*bytes.Buffer) display.Writer
+		want  []string
+	}{
+		{
+			title: "mermaid",
+			newW:  func(b *bytes.Buffer) display.Writer { return display.NewMermaidClassWriter(b) },
+			want: []string{
+				"      +Callback func#40;int#41; error\n",
+				"      -count int\n",
+			},
+		},
+		{
+			title: "plantuml",
+			newW:  func(b *bytes.Buffer) display.Writer { return display.NewPlantUMLWriter(b) },
+			want: []string{
+				"    {field} +Callback func(int) error\n",
+				"    -count int\n",
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			var (
+				buf bytes.Buffer
+				w   = tc.newW(&buf)
+			)
+			assert.Nil(t, w.Write(useOfT))
+			assert.Nil(t, w.Flush())
+			got := buf.String()
+			for _, x := range tc.want {
+				assert.Contains(t, got, x)
+			}
+		})
+	}
+}
@@ -86,12 +86,14 @@ type (
 		ID() ID
 		AttrList() AttrList
 		NodeList() NodeList
+		SubgraphList() SubgraphList
 		String() string
 	}
 
 	SubgraphConfig struct {
-		isCluster bool
-		attrList  AttrList
+		isCluster    bool
+		attrList     AttrList
+		subgraphList SubgraphList
 	}
 
 	SubgraphOption func(*SubgraphConfig)
@@ -127,9 +129,17 @@ func WithSubgraphCluster(v bool) SubgraphOption {
 	}
 }
 
-func (s *subgraph) ID() ID             { return s.id }
-func (s *subgraph) AttrList() AttrList { return s.conf.attrList }
-func (s *subgraph) NodeList() NodeList { return s.nodeList }
+// WithSubgraphSubgraphList nests the subgraphs.
+func WithSubgraphSubgraphList(subgraphList SubgraphList) SubgraphOption {
+	return func(c *SubgraphConfig) {
+		c.subgraphList = subgraphList
+	}
+}
+
+func (s *subgraph) ID() ID                     { return s.id }
+func (s *subgraph) AttrList() AttrList         { return s.conf.attrList }
+func (s *subgraph) NodeList() NodeList         { return s.nodeList }
+func (s *subgraph) SubgraphList() SubgraphList { return s.conf.subgraphList }
 
 func (s *subgraph) getID() string {
 	if s.conf.isCluster {
@@ -144,6 +154,9 @@ func (s *subgraph) String() string {
 	if s.conf.attrList != nil {
 		b.Writeln(s.conf.attrList.String(true))
 	}
+	if s.conf.subgraphList != nil {
+		b.Writeln(s.conf.subgraphList.String())
+	}
 	if s.nodeList != nil {
 		b.Writeln(s.nodeList.String())
 	}
@@ -50,6 +50,17 @@ nodeList
 		}, dot.WithSubgraphAttrList(attrList)).String())
 		assert.True(t, attrList.asStmt)
 	})
+
+	t.Run("with nodes and subgraphs", func(t *testing.T) {
+		assert.Equal(t, `subgraph cluster_g {
+subgraphList
+nodeList
+}`, dot.NewSubgraph("g", &mockNodeList{
+			str: "nodeList",
+		}, dot.WithSubgraphCluster(true), dot.WithSubgraphSubgraphList(&mockSubgraphList{
+			str: "subgraphList",
+		})).String())
+	})
 }
 
 type mockEdgeList struct {
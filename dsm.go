@@ -0,0 +1,22 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+)
+
+var dsmFormat = flag.String("dsm.format", "text", "Format of the dependency structure matrix when type is dsm. text, csv or html.")
+
+func newDSMWriter() display.Writer {
+	format, ok := display.NewDSMFormat(*dsmFormat)
+	if !ok {
+		fail(fmt.Errorf("unknown dsm format %s", *dsmFormat))
+	}
+	if *useStat {
+		return display.NewPackageDSMWriter(os.Stdout, format)
+	}
+	return display.NewNodeDSMWriter(os.Stdout, format)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+)
+
+func newFileWriter() display.Writer {
+	switch *outputType {
+	case "dot":
+		return display.NewFileDotWriter(os.Stdout, writerOptions()...)
+	case "json":
+		return display.NewFileJSONWriter(os.Stdout)
+	default:
+		fail(fmt.Errorf("type %s is not available for stat.by file", *outputType))
+		return nil
+	}
+}
+
+func newTypeWriter() display.Writer {
+	switch *outputType {
+	case "dot":
+		return display.NewNodeDotWriter(os.Stdout, append(writerOptions(), display.WithWriterFoldType(true))...)
+	case "plantuml":
+		return display.NewPlantUMLWriter(os.Stdout)
+	case "mermaid":
+		return display.NewMermaidClassWriter(os.Stdout)
+	case "json":
+		return display.NewTypeJSONWriter(os.Stdout)
+	default:
+		fail(fmt.Errorf("type %s is not available for stat.by type", *outputType))
+		return nil
+	}
+}
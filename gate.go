@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/berquerant/gotypegraph/logger"
+	"github.com/berquerant/gotypegraph/snapshot"
+)
+
+var (
+	gateBaseline = flag.String("baseline", "gotypegraph.baseline.json", "Baseline file used in gate.")
+	gateUpdate   = flag.Bool("update", false, "Rewrite the baseline file in gate.")
+	gateEdge     = flag.Bool("gate.edge", true, "Fail on new dependencies between packages in gate.")
+	gateCycle    = flag.Bool("gate.cycle", true, "Fail on new cycles of packages in gate.")
+	gateCoupling = flag.Bool("gate.coupling", true, "Fail on increases of afferent or efferent couplings of packages in gate.")
+)
+
+func runGate() {
+	current, err := buildSnapshot("")
+	fail(err)
+	if *gateUpdate {
+		fail(writeBaseline(current))
+		logger.Infof("Baseline %s updated", *gateBaseline)
+		return
+	}
+	baseline, err := readBaseline()
+	fail(err)
+	violations := snapshot.NewGate(
+		snapshot.WithGateEdge(*gateEdge),
+		snapshot.WithGateCycle(*gateCycle),
+		snapshot.WithGateCoupling(*gateCoupling),
+	).Check(baseline, current)
+	for _, x := range violations {
+		fmt.Println(x)
+	}
+	if n := len(violations); n > 0 {
+		fail(fmt.Errorf("%d violations from baseline %s", n, *gateBaseline))
+	}
+}
+
+func readBaseline() (*snapshot.Snapshot, error) {
+	f, err := os.Open(*gateBaseline)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return snapshot.Read(f)
+}
+
+func writeBaseline(s *snapshot.Snapshot) error {
+	f, err := os.Create(*gateBaseline)
+	if err != nil {
+		return err
+	}
+	if err := snapshot.Write(f, s); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,255 @@
+package graph
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+type Metric int
+
+const (
+	UnknownMetric Metric = iota
+	InDegreeMetric
+	OutDegreeMetric
+	PageRankMetric
+	BetweennessMetric
+)
+
+// Metrics returns all the known metrics.
+func Metrics() []Metric {
+	return []Metric{
+		InDegreeMetric,
+		OutDegreeMetric,
+		PageRankMetric,
+		BetweennessMetric,
+	}
+}
+
+func NewMetric(name string) Metric {
+	for _, x := range Metrics() {
+		if strings.EqualFold(x.String(), name) {
+			return x
+		}
+	}
+	return UnknownMetric
+}
+
+func (s Metric) String() string {
+	switch s {
+	case InDegreeMetric:
+		return "indegree"
+	case OutDegreeMetric:
+		return "outdegree"
+	case PageRankMetric:
+		return "pagerank"
+	case BetweennessMetric:
+		return "betweenness"
+	default:
+		return "unknown"
+	}
+}
+
+// Calculate computes the scores of the nodes.
+func (s Metric) Calculate(g Graph) Scores {
+	switch s {
+	case InDegreeMetric:
+		return InDegree(g)
+	case OutDegreeMetric:
+		return OutDegree(g)
+	case PageRankMetric:
+		return PageRank(g, defaultDamping)
+	case BetweennessMetric:
+		return Betweenness(g)
+	default:
+		return Scores{}
+	}
+}
+
+type (
+	// Scores is a metric value by node id.
+	Scores map[string]float64
+
+	Score struct {
+		ID    string
+		Value float64
+	}
+)
+
+// Top returns the n highest scores, ordered by value desc and id asc.
+// Returns all the scores if n is not positive.
+func (s Scores) Top(n int) []*Score {
+	scores := make([]*Score, 0, len(s))
+	for id, v := range s {
+		scores = append(scores, &Score{
+			ID:    id,
+			Value: v,
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Value != scores[j].Value {
+			return scores[i].Value > scores[j].Value
+		}
+		return scores[i].ID < scores[j].ID
+	})
+	if n > 0 && n < len(scores) {
+		return scores[:n]
+	}
+	return scores
+}
+
+// Ordinal returns the dense rank of the scores, 0 is the lowest.
+// This keeps the order of the scores in integers.
+func (s Scores) Ordinal() map[string]int {
+	values := make([]float64, 0, len(s))
+	for _, v := range s {
+		values = append(values, v)
+	}
+	sort.Float64s(values)
+	var (
+		rank = map[float64]int{}
+		r    int
+	)
+	for i, v := range values {
+		if i > 0 && v != values[i-1] {
+			r++
+		}
+		rank[v] = r
+	}
+	d := make(map[string]int, len(s))
+	for id, v := range s {
+		d[id] = rank[v]
+	}
+	return d
+}
+
+// InDegree is the number of the distinct nodes that refer the node, excluding itself.
+func InDegree(g Graph) Scores {
+	d := Scores{}
+	for _, x := range g.Nodes() {
+		var n int
+		for _, e := range g.In(x) {
+			if e.From() != x {
+				n++
+			}
+		}
+		d[x] = float64(n)
+	}
+	return d
+}
+
+// OutDegree is the number of the distinct nodes referred by the node, excluding itself.
+func OutDegree(g Graph) Scores {
+	d := Scores{}
+	for _, x := range g.Nodes() {
+		var n int
+		for _, e := range g.Out(x) {
+			if e.To() != x {
+				n++
+			}
+		}
+		d[x] = float64(n)
+	}
+	return d
+}
+
+const (
+	defaultDamping         = 0.85
+	pageRankMaxIterations  = 100
+	pageRankConvergenceEps = 1e-9
+)
+
+// PageRank computes the weighted PageRank.
+// A node distributes its rank to the referred nodes in proportion to the edge weights,
+// so the definitions referred by important definitions get high scores.
+// The ranks of the nodes without out edges are distributed evenly.
+func PageRank(g Graph, damping float64) Scores {
+	nodes := g.Nodes()
+	n := float64(len(nodes))
+	if n == 0 {
+		return Scores{}
+	}
+	outWeight := make(map[string]int, len(nodes))
+	for _, x := range nodes {
+		for _, e := range g.Out(x) {
+			outWeight[x] += e.Weight()
+		}
+	}
+	rank := make(map[string]float64, len(nodes))
+	for _, x := range nodes {
+		rank[x] = 1 / n
+	}
+	for i := 0; i < pageRankMaxIterations; i++ {
+		var dangling float64
+		for _, x := range nodes {
+			if outWeight[x] == 0 {
+				dangling += rank[x]
+			}
+		}
+		var (
+			next = make(map[string]float64, len(nodes))
+			diff float64
+		)
+		for _, x := range nodes {
+			v := (1-damping)/n + damping*dangling/n
+			for _, e := range g.In(x) {
+				v += damping * rank[e.From()] * float64(e.Weight()) / float64(outWeight[e.From()])
+			}
+			next[x] = v
+			diff += math.Abs(v - rank[x])
+		}
+		rank = next
+		if diff < pageRankConvergenceEps {
+			break
+		}
+	}
+	return Scores(rank)
+}
+
+// Betweenness computes the betweenness centrality by Brandes' algorithm.
+// The edge weights are ignored, the length of a path is the number of the edges.
+func Betweenness(g Graph) Scores {
+	var (
+		nodes = g.Nodes()
+		cb    = make(Scores, len(nodes))
+	)
+	for _, x := range nodes {
+		cb[x] = 0
+	}
+	for _, src := range nodes {
+		var (
+			stack = []string{}
+			pred  = map[string][]string{}
+			sigma = map[string]float64{src: 1}
+			dist  = map[string]int{src: 0}
+			queue = []string{src}
+		)
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, e := range g.Out(v) {
+				w := e.To()
+				if _, found := dist[w]; !found {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					pred[w] = append(pred[w], v)
+				}
+			}
+		}
+		delta := map[string]float64{}
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range pred[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != src {
+				cb[w] += delta[w]
+			}
+		}
+	}
+	return cb
+}
@@ -0,0 +1,78 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetric(t *testing.T) {
+	for _, x := range graph.Metrics() {
+		assert.Equal(t, x, graph.NewMetric(x.String()))
+	}
+	assert.Equal(t, graph.UnknownMetric, graph.NewMetric("closeness"))
+}
+
+func TestDegree(t *testing.T) {
+	g := newGraph(
+		edgeTuple{"a", "b", 3},
+		edgeTuple{"a", "c", 1},
+		edgeTuple{"b", "c", 1},
+		edgeTuple{"c", "c", 1}, // self loop
+	)
+	assert.Equal(t, graph.Scores{"a": 0, "b": 1, "c": 2}, graph.InDegree(g))
+	assert.Equal(t, graph.Scores{"a": 2, "b": 1, "c": 0}, graph.OutDegree(g))
+}
+
+func TestPageRank(t *testing.T) {
+	t.Run("symmetric", func(t *testing.T) {
+		got := graph.PageRank(newGraph(
+			edgeTuple{"a", "b", 1},
+			edgeTuple{"b", "a", 1},
+		), 0.85)
+		assert.InDelta(t, 0.5, got["a"], 1e-6)
+		assert.InDelta(t, 0.5, got["b"], 1e-6)
+	})
+	t.Run("weighted", func(t *testing.T) {
+		got := graph.PageRank(newGraph(
+			edgeTuple{"a", "b", 9},
+			edgeTuple{"a", "c", 1},
+		), 0.85)
+		var sum float64
+		for _, v := range got {
+			sum += v
+		}
+		assert.InDelta(t, 1, sum, 1e-6)
+		assert.Greater(t, got["b"], got["c"])
+		assert.Greater(t, got["c"], got["a"])
+	})
+}
+
+func TestBetweenness(t *testing.T) {
+	// a -> b -> c
+	//  \-> d -/
+	got := graph.Betweenness(newGraph(
+		edgeTuple{"a", "b", 1},
+		edgeTuple{"b", "c", 1},
+		edgeTuple{"a", "d", 1},
+		edgeTuple{"d", "c", 1},
+		edgeTuple{"c", "e", 1},
+	))
+	assert.Equal(t, graph.Scores{
+		"a": 0,
+		"b": 1,
+		"c": 3,
+		"d": 1,
+		"e": 0,
+	}, got)
+}
+
+func TestScores(t *testing.T) {
+	s := graph.Scores{"a": 0.5, "b": 1.5, "c": 0.5, "d": 0.1}
+	got := s.Top(3)
+	assert.Equal(t, 3, len(got))
+	assert.Equal(t, []string{"b", "a", "c"}, []string{got[0].ID, got[1].ID, got[2].ID})
+	assert.Equal(t, 4, len(s.Top(0)))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 1, "d": 0}, s.Ordinal())
+}
@@ -0,0 +1,113 @@
+package graph
+
+// Closure is the sizes of the transitive closures of a node, excluding itself.
+type Closure struct {
+	// Dependencies is the number of the nodes reachable from the node.
+	Dependencies int
+	// Dependents is the number of the nodes that reach the node.
+	Dependents int
+}
+
+// Closures computes the closures of all the nodes.
+func Closures(g Graph) map[string]*Closure {
+	var (
+		deps     = reachableCounts(g)
+		reversed = New()
+	)
+	for _, x := range g.Nodes() {
+		reversed.AddNode(x)
+	}
+	for _, e := range g.Edges() {
+		reversed.AddEdge(e.To(), e.From(), e.Weight())
+	}
+	dependents := reachableCounts(reversed)
+	d := make(map[string]*Closure, len(deps))
+	for x, n := range deps {
+		d[x] = &Closure{
+			Dependencies: n,
+			Dependents:   dependents[x],
+		}
+	}
+	return d
+}
+
+// reachableCounts returns the numbers of the reachable nodes excluding the node itself.
+// The reachable sets are computed on the condensed graph, the referred components first,
+// and a set is released once all the components that refer to it are computed.
+func reachableCounts(g Graph) map[string]int {
+	var (
+		nodes      = g.Nodes()
+		index      = make(map[string]int, len(nodes))
+		components = NewLayering(g).Components()
+		compOf     = map[string]int{}
+		succs      = make([][]int, len(components)) // the referred components
+		pending    = make([]int, len(components))   // the number of the components that refer to it and are not computed
+		reach      = make([]bitset, len(components))
+		counts     = make([]int, len(components))
+	)
+	for i, x := range nodes {
+		index[x] = i
+	}
+	for i, c := range components {
+		for _, x := range c {
+			compOf[x] = i
+		}
+	}
+	for i, c := range components {
+		seen := map[int]bool{}
+		for _, x := range c {
+			for _, e := range g.Out(x) {
+				if d := compOf[e.To()]; d != i && !seen[d] {
+					seen[d] = true
+					succs[i] = append(succs[i], d)
+					pending[d]++
+				}
+			}
+		}
+	}
+	for i, c := range components {
+		r := newBitset(len(nodes))
+		for _, x := range c {
+			r.set(index[x])
+		}
+		for _, d := range succs[i] {
+			r.union(reach[d]) // d is before i
+			if pending[d]--; pending[d] == 0 {
+				reach[d] = nil
+			}
+		}
+		counts[i] = r.count() - 1
+		if pending[i] > 0 {
+			reach[i] = r
+		}
+	}
+	d := make(map[string]int, len(nodes))
+	for i, c := range components {
+		for _, x := range c {
+			d[x] = counts[i]
+		}
+	}
+	return d
+}
+
+type bitset []uint64
+
+func newBitset(n int) bitset { return make(bitset, (n+63)/64) }
+
+func (s bitset) set(i int) { s[i/64] |= 1 << (uint(i) % 64) }
+
+func (s bitset) union(other bitset) {
+	for i, x := range other {
+		s[i] |= x
+	}
+}
+
+func (s bitset) count() int {
+	var n int
+	for _, x := range s {
+		for ; x != 0; x &= x - 1 {
+			n++
+		}
+	}
+	return n
+}
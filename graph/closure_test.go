@@ -0,0 +1,62 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClosures(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		edges []edgeTuple
+		want  map[string]*graph.Closure
+	}{
+		{
+			title: "cycle",
+			// a -> b <-> c -> d, e -> d, d -> d
+			edges: []edgeTuple{
+				{"a", "b", 1},
+				{"b", "c", 1},
+				{"c", "b", 1},
+				{"c", "d", 2},
+				{"e", "d", 1},
+				{"d", "d", 1},
+			},
+			want: map[string]*graph.Closure{
+				"a": {Dependencies: 3, Dependents: 0},
+				"b": {Dependencies: 2, Dependents: 2},
+				"c": {Dependencies: 2, Dependents: 2},
+				"d": {Dependencies: 0, Dependents: 4},
+				"e": {Dependencies: 1, Dependents: 0},
+			},
+		},
+		{
+			title: "diamond",
+			// a -> b -> d, a -> c -> d, a -> d -> e, f -> e
+			edges: []edgeTuple{
+				{"a", "b", 1},
+				{"a", "c", 1},
+				{"a", "d", 1},
+				{"b", "d", 1},
+				{"c", "d", 1},
+				{"d", "e", 1},
+				{"f", "e", 1},
+			},
+			want: map[string]*graph.Closure{
+				"a": {Dependencies: 4, Dependents: 0},
+				"b": {Dependencies: 2, Dependents: 1},
+				"c": {Dependencies: 2, Dependents: 1},
+				"d": {Dependencies: 1, Dependents: 3},
+				"e": {Dependencies: 0, Dependents: 5},
+				"f": {Dependencies: 1, Dependents: 0},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			assert.Equal(t, tc.want, graph.Closures(newGraph(tc.edges...)))
+		})
+	}
+}
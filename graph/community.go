@@ -0,0 +1,215 @@
+package graph
+
+import "sort"
+
+// Communities detects the communities by the Louvain method.
+// The graph is treated as undirected, the weight between two nodes is the sum of the weights of both directions,
+// self loops are ignored.
+// Each community is sorted, the communities are sorted by the first node.
+func Communities(g Graph) [][]string {
+	var (
+		nodes = g.Nodes()
+		index = make(map[string]int, len(nodes))
+	)
+	for i, x := range nodes {
+		index[x] = i
+	}
+	u := newUndirected(len(nodes))
+	for _, e := range g.Edges() {
+		if e.From() != e.To() {
+			u.add(index[e.From()], index[e.To()], float64(e.Weight()))
+		}
+	}
+
+	membership := make([]int, len(nodes)) // node => community
+	for i := range membership {
+		membership[i] = i
+	}
+	for {
+		comm, improved := u.moveNodes()
+		if !improved {
+			break
+		}
+		for i, c := range membership {
+			membership[i] = comm[c]
+		}
+		u = u.aggregate(comm)
+	}
+
+	d := map[int][]string{}
+	for i, c := range membership {
+		d[c] = append(d[c], nodes[i])
+	}
+	communities := make([][]string, 0, len(d))
+	for _, c := range d {
+		sort.Strings(c)
+		communities = append(communities, c)
+	}
+	sort.Slice(communities, func(i, j int) bool { return communities[i][0] < communities[j][0] })
+	return communities
+}
+
+// ConnectedComponents returns the connected components ignoring the directions of the edges.
+// Each component is sorted, the components are sorted by the first node.
+func ConnectedComponents(g Graph) [][]string {
+	var (
+		visited    = map[string]bool{}
+		components [][]string
+	)
+	for _, v := range g.Nodes() {
+		if visited[v] {
+			continue
+		}
+		var (
+			c     []string
+			stack = []string{v}
+		)
+		visited[v] = true
+		for len(stack) > 0 {
+			x := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			c = append(c, x)
+			var next []string
+			for _, e := range g.Out(x) {
+				next = append(next, e.To())
+			}
+			for _, e := range g.In(x) {
+				next = append(next, e.From())
+			}
+			for _, y := range next {
+				if !visited[y] {
+					visited[y] = true
+					stack = append(stack, y)
+				}
+			}
+		}
+		sort.Strings(c)
+		components = append(components, c)
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+// undirected is a weighted undirected graph whose nodes are 0 to n-1.
+type undirected struct {
+	adj    []map[int]float64 // except self loops
+	loop   []float64
+	degree []float64 // self loops are counted twice
+	total  float64   // sum of the degrees
+}
+
+func newUndirected(n int) *undirected {
+	adj := make([]map[int]float64, n)
+	for i := range adj {
+		adj[i] = map[int]float64{}
+	}
+	return &undirected{
+		adj:    adj,
+		loop:   make([]float64, n),
+		degree: make([]float64, n),
+	}
+}
+
+func (s *undirected) add(i, j int, w float64) {
+	if i == j {
+		s.loop[i] += w
+		s.degree[i] += 2 * w
+		s.total += 2 * w
+		return
+	}
+	s.adj[i][j] += w
+	s.adj[j][i] += w
+	s.degree[i] += w
+	s.degree[j] += w
+	s.total += 2 * w
+}
+
+func (s *undirected) neighbors(i int) []int {
+	xs := make([]int, 0, len(s.adj[i]))
+	for j := range s.adj[i] {
+		xs = append(xs, j)
+	}
+	sort.Ints(xs)
+	return xs
+}
+
+const modularityEps = 1e-12
+
+// moveNodes moves the nodes to the neighbor communities while the modularity increases.
+// Returns the renumbered communities of the nodes.
+func (s *undirected) moveNodes() ([]int, bool) {
+	n := len(s.adj)
+	comm := make([]int, n)
+	tot := make([]float64, n)
+	for i := 0; i < n; i++ {
+		comm[i] = i
+		tot[i] = s.degree[i]
+	}
+	if s.total == 0 {
+		return comm, false
+	}
+	var improved bool
+	for moved := true; moved; {
+		moved = false
+		for i := 0; i < n; i++ {
+			var (
+				current = comm[i]
+				weights = map[int]float64{}
+				order   []int
+			)
+			for _, j := range s.neighbors(i) {
+				c := comm[j]
+				if _, found := weights[c]; !found {
+					order = append(order, c)
+				}
+				weights[c] += s.adj[i][j]
+			}
+			tot[current] -= s.degree[i]
+			var (
+				best     = current
+				bestGain = weights[current] - tot[current]*s.degree[i]/s.total
+			)
+			for _, c := range order {
+				if gain := weights[c] - tot[c]*s.degree[i]/s.total; gain > bestGain+modularityEps {
+					best = c
+					bestGain = gain
+				}
+			}
+			tot[best] += s.degree[i]
+			if best != current {
+				comm[i] = best
+				moved = true
+				improved = true
+			}
+		}
+	}
+
+	renumber := map[int]int{}
+	for i, c := range comm {
+		if _, found := renumber[c]; !found {
+			renumber[c] = len(renumber)
+		}
+		comm[i] = renumber[c]
+	}
+	return comm, improved
+}
+
+// aggregate makes a graph whose nodes are the communities.
+func (s *undirected) aggregate(comm []int) *undirected {
+	var n int
+	for _, c := range comm {
+		if c+1 > n {
+			n = c + 1
+		}
+	}
+	u := newUndirected(n)
+	for i := range s.adj {
+		u.add(comm[i], comm[i], s.loop[i])
+		for j, w := range s.adj[i] {
+			if i < j {
+				u.add(comm[i], comm[j], w)
+			}
+		}
+	}
+	return u
+}
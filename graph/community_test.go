@@ -0,0 +1,49 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommunities(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, 0, len(graph.Communities(graph.New())))
+	})
+
+	t.Run("isolated", func(t *testing.T) {
+		g := newGraph(edgeTuple{"a", "a", 1})
+		g.AddNode("b")
+		assert.Equal(t, [][]string{{"a"}, {"b"}}, graph.Communities(g))
+	})
+
+	// two triangles connected by a weak edge
+	g := newGraph(
+		edgeTuple{"a", "b", 3},
+		edgeTuple{"b", "c", 2},
+		edgeTuple{"c", "a", 2},
+		edgeTuple{"d", "e", 3},
+		edgeTuple{"e", "f", 2},
+		edgeTuple{"f", "d", 2},
+		edgeTuple{"c", "d", 1},
+	)
+	assert.Equal(t, [][]string{
+		{"a", "b", "c"},
+		{"d", "e", "f"},
+	}, graph.Communities(g))
+}
+
+func TestConnectedComponents(t *testing.T) {
+	g := newGraph(
+		edgeTuple{"a", "b", 1},
+		edgeTuple{"c", "b", 1},
+		edgeTuple{"d", "e", 1},
+		edgeTuple{"f", "f", 1},
+	)
+	assert.Equal(t, [][]string{
+		{"a", "b", "c"},
+		{"d", "e"},
+		{"f"},
+	}, graph.ConnectedComponents(g))
+}
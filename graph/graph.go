@@ -0,0 +1,136 @@
+package graph
+
+import "sort"
+
+type (
+	// Graph is a weighted directed graph.
+	Graph interface {
+		AddNode(id string) Graph
+		AddEdge(from, to string, weight int) Graph
+		HasNode(id string) bool
+		// Nodes returns the sorted node ids.
+		Nodes() []string
+		// Edges returns the edges sorted by from and to.
+		Edges() []Edge
+		// Out returns the edges from the node, sorted by to.
+		Out(id string) []Edge
+		// In returns the edges to the node, sorted by from.
+		In(id string) []Edge
+	}
+
+	Edge interface {
+		From() string
+		To() string
+		Weight() int
+	}
+
+	edge struct {
+		from   string
+		to     string
+		weight int
+	}
+)
+
+func NewEdge(from, to string, weight int) Edge {
+	return &edge{
+		from:   from,
+		to:     to,
+		weight: weight,
+	}
+}
+
+func (s *edge) From() string { return s.from }
+func (s *edge) To() string   { return s.to }
+func (s *edge) Weight() int  { return s.weight }
+
+func New() Graph {
+	return &graph{
+		nodes: map[string]bool{},
+		out:   map[string]map[string]int{},
+		in:    map[string]map[string]int{},
+	}
+}
+
+type graph struct {
+	nodes map[string]bool
+	out   map[string]map[string]int // from => to => weight
+	in    map[string]map[string]int // to => from => weight
+}
+
+func (s *graph) AddNode(id string) Graph {
+	s.nodes[id] = true
+	return s
+}
+
+// AddEdge adds an edge, the weight is accumulated if the edge already exists.
+func (s *graph) AddEdge(from, to string, weight int) Graph {
+	s.AddNode(from)
+	s.AddNode(to)
+	if _, ok := s.out[from]; !ok {
+		s.out[from] = map[string]int{}
+	}
+	if _, ok := s.in[to]; !ok {
+		s.in[to] = map[string]int{}
+	}
+	s.out[from][to] += weight
+	s.in[to][from] += weight
+	return s
+}
+
+func (s *graph) HasNode(id string) bool { return s.nodes[id] }
+
+func (s *graph) Nodes() []string {
+	var (
+		i     int
+		nodes = make([]string, len(s.nodes))
+	)
+	for x := range s.nodes {
+		nodes[i] = x
+		i++
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+func (s *graph) Edges() []Edge {
+	edges := []Edge{}
+	for _, from := range s.Nodes() {
+		edges = append(edges, s.Out(from)...)
+	}
+	return edges
+}
+
+func (s *graph) Out(id string) []Edge {
+	var (
+		d     = s.out[id]
+		edges = make([]Edge, 0, len(d))
+	)
+	for _, to := range sortedKeys(d) {
+		edges = append(edges, NewEdge(id, to, d[to]))
+	}
+	return edges
+}
+
+func (s *graph) In(id string) []Edge {
+	var (
+		d     = s.in[id]
+		edges = make([]Edge, 0, len(d))
+	)
+	for _, from := range sortedKeys(d) {
+		edges = append(edges, NewEdge(from, id, d[from]))
+	}
+	return edges
+}
+
+func sortedKeys(d map[string]int) []string {
+	var (
+		i    int
+		keys = make([]string, len(d))
+	)
+	for k := range d {
+		keys[i] = k
+		i++
+	}
+	sort.Strings(keys)
+	return keys
+}
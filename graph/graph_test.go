@@ -0,0 +1,61 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+type edgeTuple struct {
+	from   string
+	to     string
+	weight int
+}
+
+func newGraph(edges ...edgeTuple) graph.Graph {
+	g := graph.New()
+	for _, e := range edges {
+		g.AddEdge(e.from, e.to, e.weight)
+	}
+	return g
+}
+
+func toTuples(edges []graph.Edge) []edgeTuple {
+	r := make([]edgeTuple, len(edges))
+	for i, e := range edges {
+		r[i] = edgeTuple{
+			from:   e.From(),
+			to:     e.To(),
+			weight: e.Weight(),
+		}
+	}
+	return r
+}
+
+func TestGraph(t *testing.T) {
+	g := newGraph(
+		edgeTuple{"b", "a", 1},
+		edgeTuple{"a", "c", 2},
+		edgeTuple{"a", "b", 1},
+		edgeTuple{"a", "c", 1},
+	)
+	g.AddNode("d")
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, g.Nodes())
+	assert.True(t, g.HasNode("d"))
+	assert.False(t, g.HasNode("e"))
+	assert.Equal(t, []edgeTuple{
+		{"a", "b", 1},
+		{"a", "c", 3},
+		{"b", "a", 1},
+	}, toTuples(g.Edges()))
+	assert.Equal(t, []edgeTuple{
+		{"a", "b", 1},
+		{"a", "c", 3},
+	}, toTuples(g.Out("a")))
+	assert.Equal(t, []edgeTuple{
+		{"b", "a", 1},
+	}, toTuples(g.In("a")))
+	assert.Equal(t, 0, len(g.Out("d")))
+}
@@ -0,0 +1,196 @@
+package graph
+
+import "sort"
+
+// StronglyConnectedComponents returns the strongly connected components by Tarjan's algorithm.
+// Each component is sorted, the components are sorted by the first node.
+func StronglyConnectedComponents(g Graph) [][]string {
+	var (
+		index      = map[string]int{}
+		lowlink    = map[string]int{}
+		onStack    = map[string]bool{}
+		stack      []string
+		components [][]string
+		next       int
+		visit      func(v string)
+	)
+	visit = func(v string) {
+		index[v] = next
+		lowlink[v] = next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, e := range g.Out(v) {
+			w := e.To()
+			if _, found := index[w]; !found {
+				visit(w)
+				if lowlink[w] < lowlink[v] {
+					lowlink[v] = lowlink[w]
+				}
+			} else if onStack[w] && index[w] < lowlink[v] {
+				lowlink[v] = index[w]
+			}
+		}
+		if lowlink[v] != index[v] {
+			return
+		}
+		var c []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			c = append(c, w)
+			if w == v {
+				break
+			}
+		}
+		sort.Strings(c)
+		components = append(components, c)
+	}
+	for _, v := range g.Nodes() {
+		if _, found := index[v]; !found {
+			visit(v)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+// Cycles returns the strongly connected components that have more than one node.
+func Cycles(g Graph) [][]string {
+	cycles := [][]string{}
+	for _, c := range StronglyConnectedComponents(g) {
+		if len(c) > 1 {
+			cycles = append(cycles, c)
+		}
+	}
+	return cycles
+}
+
+// Layering is the layers of the graph whose cycles are condensed.
+// An edge goes from a upper layer to a lower layer,
+// the nodes without out edges are in the layer 0.
+type Layering interface {
+	// Layer returns the layer number of the node.
+	Layer(id string) (int, bool)
+	// Layers returns the sorted nodes by layer number.
+	Layers() [][]string
+	// Order returns the nodes in topological order, the referred nodes first.
+	Order() []string
+	// Components returns the strongly connected components in topological order.
+	Components() [][]string
+	// BackEdges returns the edges that violate the layers,
+	// the edges in the same strongly connected components except self loops.
+	BackEdges() []Edge
+}
+
+func NewLayering(g Graph) Layering {
+	var (
+		components = StronglyConnectedComponents(g)
+		compOf     = map[string]int{}
+	)
+	for i, c := range components {
+		for _, x := range c {
+			compOf[x] = i
+		}
+	}
+
+	var (
+		compLayer = make([]int, len(components))
+		done      = make([]bool, len(components))
+		visit     func(c int) int
+	)
+	visit = func(c int) int {
+		if done[c] {
+			return compLayer[c]
+		}
+		done[c] = true // the condensed graph is a DAG
+		var layer int
+		for _, x := range components[c] {
+			for _, e := range g.Out(x) {
+				d := compOf[e.To()]
+				if d == c {
+					continue
+				}
+				if l := visit(d) + 1; l > layer {
+					layer = l
+				}
+			}
+		}
+		compLayer[c] = layer
+		return layer
+	}
+	for i := range components {
+		visit(i)
+	}
+
+	order := make([]int, len(components))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return compLayer[order[i]] < compLayer[order[j]] })
+	sortedComponents := make([][]string, len(components))
+	for i, c := range order {
+		sortedComponents[i] = components[c]
+	}
+
+	layer := map[string]int{}
+	for x, c := range compOf {
+		layer[x] = compLayer[c]
+	}
+
+	backEdges := []Edge{}
+	for _, e := range g.Edges() {
+		if e.From() != e.To() && compOf[e.From()] == compOf[e.To()] {
+			backEdges = append(backEdges, e)
+		}
+	}
+	return &layering{
+		layer:      layer,
+		components: sortedComponents,
+		backEdges:  backEdges,
+	}
+}
+
+type layering struct {
+	layer      map[string]int // node => layer
+	components [][]string
+	backEdges  []Edge
+}
+
+func (s *layering) Layer(id string) (int, bool) {
+	x, ok := s.layer[id]
+	return x, ok
+}
+
+func (s *layering) Layers() [][]string {
+	if len(s.layer) == 0 {
+		return [][]string{}
+	}
+	var max int
+	for _, x := range s.layer {
+		if x > max {
+			max = x
+		}
+	}
+	layers := make([][]string, max+1)
+	for _, c := range s.components {
+		l := s.layer[c[0]]
+		layers[l] = append(layers[l], c...)
+	}
+	for _, x := range layers {
+		sort.Strings(x)
+	}
+	return layers
+}
+
+func (s *layering) Order() []string {
+	order := []string{}
+	for _, c := range s.components {
+		order = append(order, c...)
+	}
+	return order
+}
+
+func (s *layering) Components() [][]string { return s.components }
+func (s *layering) BackEdges() []Edge      { return s.backEdges }
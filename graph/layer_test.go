@@ -0,0 +1,90 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := newGraph(
+		edgeTuple{"a", "b", 1},
+		edgeTuple{"b", "c", 1},
+		edgeTuple{"c", "a", 1},
+		edgeTuple{"c", "d", 1},
+		edgeTuple{"d", "d", 1},
+		edgeTuple{"e", "d", 1},
+	)
+	assert.Equal(t, [][]string{
+		{"a", "b", "c"},
+		{"d"},
+		{"e"},
+	}, graph.StronglyConnectedComponents(g))
+	assert.Equal(t, [][]string{
+		{"a", "b", "c"},
+	}, graph.Cycles(g))
+}
+
+func TestLayering(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		l := graph.NewLayering(graph.New())
+		assert.Equal(t, 0, len(l.Layers()))
+		assert.Equal(t, 0, len(l.Order()))
+		assert.Equal(t, 0, len(l.BackEdges()))
+	})
+
+	// main -> app -> domain -> util
+	//            \-> infra <-> infra2
+	//                  \-> util
+	g := newGraph(
+		edgeTuple{"main", "app", 1},
+		edgeTuple{"app", "domain", 1},
+		edgeTuple{"app", "infra", 1},
+		edgeTuple{"domain", "util", 1},
+		edgeTuple{"infra", "infra2", 2},
+		edgeTuple{"infra2", "infra", 1},
+		edgeTuple{"infra", "util", 1},
+		edgeTuple{"util", "util", 1},
+	)
+	l := graph.NewLayering(g)
+
+	assert.Equal(t, [][]string{
+		{"util"},
+		{"domain", "infra", "infra2"},
+		{"app"},
+		{"main"},
+	}, l.Layers())
+	for id, want := range map[string]int{
+		"util":   0,
+		"domain": 1,
+		"infra":  1,
+		"infra2": 1,
+		"app":    2,
+		"main":   3,
+	} {
+		got, ok := l.Layer(id)
+		assert.True(t, ok)
+		assert.Equal(t, want, got, id)
+	}
+	_, ok := l.Layer("none")
+	assert.False(t, ok)
+
+	order := l.Order()
+	pos := map[string]int{}
+	for i, x := range order {
+		pos[x] = i
+	}
+	assert.Equal(t, 6, len(order))
+	for _, e := range g.Edges() {
+		if e.From() != e.To() && !(e.From() == "infra2" || e.To() == "infra2") { // not in cycle
+			assert.Less(t, pos[e.To()], pos[e.From()], "%s -> %s", e.From(), e.To())
+		}
+	}
+
+	assert.Equal(t, []edgeTuple{
+		{"infra", "infra2", 2},
+		{"infra2", "infra", 1},
+	}, toTuples(l.BackEdges()))
+	assert.Equal(t, 5, len(l.Components()))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/rule"
+	"github.com/berquerant/gotypegraph/stat"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	groupsFile = flag.String("groups", "", "Groups file, aggregate packages into the groups.")
+	statBy     = flag.String("stat.by", "pkg", "Aggregation of the stat graph. pkg, module, dir, file or type.")
+	statDepth  = flag.Int("stat.depth", 3, "Number of path elements of the directory prefix when stat.by is dir.")
+)
+
+// useGroup returns true if the packages are aggregated into the groups.
+func useGroup() bool {
+	if *groupsFile != "" {
+		return true
+	}
+	switch *statBy {
+	case "module", "dir":
+		return true
+	default:
+		return false
+	}
+}
+
+func readGroups() stat.GroupFunc {
+	f, err := os.Open(*groupsFile)
+	fail(err)
+	defer f.Close()
+	mapper, err := rule.ParseGroups(f)
+	fail(err)
+	return func(pkg stat.Pkg) stat.Group {
+		return stat.NewGroup(mapper.Group(pkg.ID()))
+	}
+}
+
+// newGroupFunc returns the function to group the packages, the modules are found in pkgs.
+func newGroupFunc(pkgs []*packages.Package) stat.GroupFunc {
+	if *groupsFile != "" {
+		return readGroups()
+	}
+	switch *statBy {
+	case "module":
+		return stat.NewModuleGroupFunc(pkgs)
+	case "dir":
+		return stat.NewDirGroupFunc(*statDepth)
+	default:
+		fail(fmt.Errorf("unknown stat.by %s", *statBy))
+		return nil
+	}
+}
+
+func newGroupWriter(pkgs []*packages.Package) display.Writer {
+	switch *outputType {
+	case "dot":
+		return display.NewGroupDotWriter(os.Stdout, newGroupFunc(pkgs), writerOptions()...)
+	case "json":
+		return display.NewGroupJSONWriter(os.Stdout, newGroupFunc(pkgs))
+	default:
+		fail(fmt.Errorf("type %s is not available for groups", *outputType))
+		return nil
+	}
+}
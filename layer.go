@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/search"
+)
+
+func runLayer() {
+	profiler := newProfiler()
+	profiler.Init()
+	pkgs := loadPackages()
+	profiler.PkgLoaded(pkgs)
+	var (
+		searcher = newSearcher(
+			pkgs,
+			extractDefSetList(pkgs),
+			append(searcherOptions(), search.WithUseSearcherIgnorePkgSelfloop(true))...,
+		)
+		writer = display.NewLayerWriter(os.Stdout)
+	)
+	write(profiler, searcher, writer)
+}
@@ -1,20 +1,91 @@
 package load
 
-import "golang.org/x/tools/go/packages"
+import (
+	"strings"
 
-type Loader interface {
-	Load(patterns ...string) ([]*packages.Package, error)
+	"golang.org/x/tools/go/packages"
+)
+
+type (
+	Loader interface {
+		Load(patterns ...string) ([]*packages.Package, error)
+	}
+
+	LoaderConfig struct {
+		tests bool
+		dir   string
+	}
+
+	LoaderOption func(*LoaderConfig)
+)
+
+func New(opt ...LoaderOption) Loader {
+	var config LoaderConfig
+	for _, x := range opt {
+		x(&config)
+	}
+	return &loader{
+		conf: &config,
+	}
+}
+
+// WithLoaderTests loads test files too.
+// The package variants augmented with tests replace the original packages.
+func WithLoaderTests(v bool) LoaderOption {
+	return func(c *LoaderConfig) {
+		c.tests = v
+	}
 }
 
-func New() Loader { return &loader{} }
+// WithLoaderDir loads packages in the directory instead of the current directory.
+func WithLoaderDir(v string) LoaderOption {
+	return func(c *LoaderConfig) {
+		c.dir = v
+	}
+}
 
-type loader struct{}
+type loader struct {
+	conf *LoaderConfig
+}
 
 const loadMode = packages.NeedTypesInfo | packages.NeedTypes | packages.NeedName |
-	packages.NeedSyntax | packages.NeedImports
+	packages.NeedSyntax | packages.NeedImports | packages.NeedModule
 
-func (*loader) Load(patterns ...string) ([]*packages.Package, error) {
-	return packages.Load(&packages.Config{
-		Mode: loadMode,
+func (s *loader) Load(patterns ...string) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:  loadMode,
+		Tests: s.conf.tests,
+		Dir:   s.conf.dir,
 	}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if !s.conf.tests {
+		return pkgs, nil
+	}
+	return selectTestVariants(pkgs), nil
+}
+
+// selectTestVariants removes the generated test mains and
+// the packages that have the variants augmented with tests.
+func selectTestVariants(pkgs []*packages.Package) []*packages.Package {
+	var (
+		selected = []*packages.Package{}
+		index    = map[string]int{} // pkg path => index of selected
+	)
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		i, found := index[pkg.PkgPath]
+		switch {
+		case !found:
+			index[pkg.PkgPath] = len(selected)
+			selected = append(selected, pkg)
+		case pkg.ID != pkg.PkgPath:
+			// test variant, e.g. "p [p.test]"
+			selected[i] = pkg
+		}
+	}
+	return selected
 }
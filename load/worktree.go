@@ -0,0 +1,66 @@
+package load
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/logger"
+)
+
+// Worktree is a temporary checkout of a git revision of the repository of the current directory.
+type Worktree interface {
+	// Dir returns the directory in the worktree that corresponds to the current directory.
+	Dir() string
+	// Close removes the worktree.
+	Close() error
+}
+
+// NewWorktree checks out the revision by git worktree.
+func NewWorktree(rev string) (Worktree, error) {
+	prefix, err := git("rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, fmt.Errorf("worktree %s: %w", rev, err)
+	}
+	root, err := os.MkdirTemp("", "gotypegraph-worktree-")
+	if err != nil {
+		return nil, fmt.Errorf("worktree %s: %w", rev, err)
+	}
+	if _, err := git("worktree", "add", "--detach", root, rev); err != nil {
+		os.RemoveAll(root)
+		return nil, fmt.Errorf("worktree %s: %w", rev, err)
+	}
+	logger.Verbosef("[Worktree] add %s %s", rev, root)
+	return &worktree{
+		root: root,
+		dir:  filepath.Join(root, prefix),
+	}, nil
+}
+
+type worktree struct {
+	root string
+	dir  string
+}
+
+func (s *worktree) Dir() string { return s.dir }
+func (s *worktree) Close() error {
+	logger.Verbosef("[Worktree] remove %s", s.root)
+	if _, err := git("worktree", "remove", "--force", s.root); err != nil {
+		return fmt.Errorf("worktree %s: %w", s.root, err)
+	}
+	return nil
+}
+
+func git(arg ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", arg...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(arg, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
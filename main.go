@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/graph"
 	"github.com/berquerant/gotypegraph/load"
 	"github.com/berquerant/gotypegraph/logger"
 	"github.com/berquerant/gotypegraph/profile"
@@ -17,8 +18,10 @@ import (
 )
 
 var (
-	outputType       = flag.String("type", "dot", "Output format. json or dot.")
-	useStat          = flag.Bool("stat", false, "Generate stat graph when type is dot.")
+	outputType       = flag.String("type", "dot", "Output format. json, json-stat, dot, mermaid, graphml, gexf, cytoscape, html, csv, tsv, sqlite, cypher, dsm or tree, text is also available for diff and cluster, plantuml for stat.by type.")
+	useStat          = flag.Bool("stat", false, "Generate stat graph of the packages when type is not json.")
+	useLayer         = flag.Bool("layer", false, "Draw packages in layers when type is dot and stat.")
+	useClosure       = flag.Bool("closure", false, "Add the sizes of the transitive closures to the labels when type is dot.")
 	searchForeign    = flag.Bool("foreign", false, "Search definitions in foreign packages.")
 	searchUniverse   = flag.Bool("universe", false, "Search definitions in builtin packages.")
 	searchPrivate    = flag.Bool("private", false, "Search private definitions.")
@@ -35,6 +38,7 @@ var (
 	maxPenwidth      = flag.Int("penwidth.max", 1, "Max penwidth used to draw lines in dot.")
 	minWeight        = flag.Int("weight.min", 1, "Min weight for dot.")
 	maxWeight        = flag.Int("weight.max", 100, "Max weight for dot.")
+	dotMetric        = flag.String("metric", "", "Metric to determine fontsize and penwidth in dot instead of weight. indegree, outdegree, pagerank or betweenness.")
 
 	verbosity = flag.String("v", "info", "Logging verbosity. quiet, error, warn, info, verbose or debug.")
 	quiet     = flag.Bool("quiet", false, "Quiet logs.")
@@ -43,10 +47,15 @@ var (
 
 const usage = `Usage of gotypegraph:
   gotypegraph [flags] -type TYPE patterns...
-Flags:`
+  gotypegraph COMMAND [flags] patterns...
+Commands:`
 
 func Usage() {
 	fmt.Fprintln(os.Stderr, usage)
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %s\n        %s\n", c.name, c.desc)
+	}
+	fmt.Fprintln(os.Stderr, "Flags:")
 	flag.PrintDefaults()
 }
 
@@ -93,24 +102,90 @@ func compileRegex(v string) *regexp.Regexp {
 	return regexp.MustCompile(v)
 }
 
-func loadPackages() []*packages.Package {
-	pkgs, err := load.New().Load(flag.Args()...)
+func parseMetric(name string) graph.Metric {
+	m := graph.NewMetric(strings.TrimSpace(name))
+	if m == graph.UnknownMetric {
+		fail(fmt.Errorf("unknown metric %s", name))
+	}
+	return m
+}
+
+func loadPackages(opt ...load.LoaderOption) []*packages.Package {
+	logger.Infof("Load packages")
+	pkgs, err := load.New(opt...).Load(flag.Args()...)
 	fail(err)
+	logger.Infof("%d packages loaded", len(pkgs))
 	return pkgs
 }
 
 func writerOptions() []display.WriterOption {
-	return []display.WriterOption{
+	opt := []display.WriterOption{
 		display.WithWriterMinFontsize(*minFontsize),
 		display.WithWriterMaxFontsize(*maxFontsize),
 		display.WithWriterMinPenwidth(*minPenwidth),
 		display.WithWriterMaxPenwidth(*maxPenwidth),
 		display.WithWriterMinWeight(*minWeight),
 		display.WithWriterMaxWeight(*maxWeight),
+		display.WithWriterLayer(*useLayer),
+		display.WithWriterClosure(*useClosure),
+	}
+	if *dotMetric != "" {
+		opt = append(opt, display.WithWriterMetric(parseMetric(*dotMetric)))
+	}
+	return opt
+}
+
+// validateFlags fails on the invalid flags of the writer, it does not need the packages.
+func validateFlags() {
+	switch *statBy {
+	case "pkg", "module", "dir", "file", "type":
+	default:
+		fail(fmt.Errorf("unknown stat.by %s", *statBy))
+	}
+	switch {
+	case useGroup():
+		switch *outputType {
+		case "dot", "json":
+		default:
+			fail(fmt.Errorf("type %s is not available for groups", *outputType))
+		}
+	case *statBy == "file":
+		switch *outputType {
+		case "dot", "json":
+		default:
+			fail(fmt.Errorf("type %s is not available for stat.by file", *outputType))
+		}
+	case *statBy == "type":
+		switch *outputType {
+		case "dot", "plantuml", "mermaid", "json":
+		default:
+			fail(fmt.Errorf("type %s is not available for stat.by type", *outputType))
+		}
+	default:
+		switch *outputType {
+		case "dot", "mermaid", "graphml", "gexf", "cytoscape", "html", "csv", "tsv", "sqlite", "cypher", "tree", "json-stat", "json":
+		case "dsm":
+			if _, ok := display.NewDSMFormat(*dsmFormat); !ok {
+				fail(fmt.Errorf("unknown dsm format %s", *dsmFormat))
+			}
+		default:
+			fail(fmt.Errorf("unknown type %s", *outputType))
+		}
 	}
 }
 
-func newWriter() display.Writer {
+// newWriter returns the writer of the graph, pkgs are the loaded packages.
+func newWriter(pkgs []*packages.Package) display.Writer {
+	validateFlags()
+	if useGroup() {
+		return newGroupWriter(pkgs)
+	}
+	switch *statBy {
+	case "file":
+		return newFileWriter()
+	case "type":
+		return newTypeWriter()
+	}
 	switch *outputType {
 	case "dot":
 		opt := writerOptions()
@@ -118,20 +193,75 @@ func newWriter() display.Writer {
 			return display.NewPackageDotWriter(os.Stdout, opt...)
 		}
 		return display.NewNodeDotWriter(os.Stdout, opt...)
-	default:
+	case "mermaid":
+		if *useStat {
+			return display.NewPackageMermaidWriter(os.Stdout)
+		}
+		return display.NewNodeMermaidWriter(os.Stdout)
+	case "graphml":
+		if *useStat {
+			return display.NewPackageGraphMLWriter(os.Stdout)
+		}
+		return display.NewNodeGraphMLWriter(os.Stdout)
+	case "gexf":
+		if *useStat {
+			return display.NewPackageGEXFWriter(os.Stdout)
+		}
+		return display.NewNodeGEXFWriter(os.Stdout)
+	case "cytoscape":
+		if *useStat {
+			return display.NewPackageCytoscapeWriter(os.Stdout)
+		}
+		return display.NewNodeCytoscapeWriter(os.Stdout)
+	case "html":
+		if *useStat {
+			return display.NewPackageHTMLWriter(os.Stdout)
+		}
+		return display.NewNodeHTMLWriter(os.Stdout)
+	case "csv", "tsv":
+		return newTableWriter()
+	case "sqlite":
+		return newSQLiteWriter()
+	case "cypher":
+		return display.NewCypherWriter(os.Stdout)
+	case "dsm":
+		return newDSMWriter()
+	case "tree":
+		return newTreeWriter()
+	case "json-stat":
+		if *useStat {
+			return display.NewPackageJSONStatWriter(os.Stdout)
+		}
+		return display.NewNodeJSONStatWriter(os.Stdout)
+	case "json":
 		return display.NewJSONWriter(os.Stdout)
+	default:
+		fail(fmt.Errorf("unknown type %s", *outputType))
+		return nil
 	}
 }
 
-func ignoreSelfloopOptions() []search.UseSearcherOption {
+// ignoreSelfloops returns whether to ignore the self references of the packages and the definitions.
+func ignoreSelfloops() (pkg, use bool) {
 	if !*ignoreSelfloop {
-		return nil
+		return false, false
 	}
 	switch *outputType {
-	case "dot":
-		if *useStat {
-			return []search.UseSearcherOption{search.WithUseSearcherIgnorePkgSelfloop(true)}
+	case "dot", "mermaid", "graphml", "gexf", "cytoscape", "html", "csv", "tsv", "cypher", "dsm", "tree", "json-stat":
+		if (*useStat && *statBy == "pkg") || useGroup() {
+			return true, false
 		}
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+func ignoreSelfloopOptions() []search.UseSearcherOption {
+	switch pkg, use := ignoreSelfloops(); {
+	case pkg:
+		return []search.UseSearcherOption{search.WithUseSearcherIgnorePkgSelfloop(true)}
+	case use:
 		return []search.UseSearcherOption{search.WithUseSearcherIgnoreUseSelfloop(true)}
 	default:
 		return nil
@@ -156,7 +286,7 @@ func searcherOptions() []search.UseSearcherOption {
 	}, ignoreSelfloopOptions()...)
 }
 
-func newSearcher(pkgs []*packages.Package, opt ...search.UseSearcherOption) search.UseSearcher {
+func extractDefSetList(pkgs []*packages.Package) []search.DefSet {
 	var (
 		defSetExtractor = search.NewDefSetExtractor(search.NewDefExtractor())
 		defSetList      = make([]search.DefSet, len(pkgs))
@@ -164,13 +294,26 @@ func newSearcher(pkgs []*packages.Package, opt ...search.UseSearcherOption) sear
 	for i, pkg := range pkgs {
 		defSetList[i] = defSetExtractor.Extract(pkg)
 	}
+	return defSetList
+}
+
+func newSearcher(pkgs []*packages.Package, defSetList []search.DefSet, opt ...search.UseSearcherOption) search.UseSearcher {
+	return newSearcherWithFilter(pkgs, defSetList, search.DefSetFilter(defSetList), opt...)
+}
+
+func newSearcherWithFilter(
+	pkgs []*packages.Package,
+	defSetList []search.DefSet,
+	defSetFilter search.Filter,
+	opt ...search.UseSearcherOption,
+) search.UseSearcher {
 	return search.NewUseSearcher(
 		pkgs,
 		search.NewRefPkgSearcher(search.NewRefSearcher(), defSetList),
 		search.NewObjExtractor(),
 		search.NewTargetExtractor(),
 		search.NewFieldSearcherFromPackages(pkgs),
-		search.DefSetFilter(defSetList),
+		defSetFilter,
 		opt...,
 	)
 }
@@ -184,22 +327,30 @@ func newProfiler() profile.Profiler {
 
 func main() {
 	flag.Usage = Usage
-	flag.Parse()
-
+	cmd := parseCommand()
 	initLogger()
+	cmd.run()
+}
+
+func runGraph() {
+	// fail on the invalid flags before loading the packages
+	validateFlags()
 	profiler := newProfiler()
 	profiler.Init()
-	logger.Infof("Load packages")
 	pkgs := loadPackages()
 	profiler.PkgLoaded(pkgs)
-	logger.Infof("%d packages loaded", len(pkgs))
 	var (
 		searcher = newSearcher(
 			pkgs,
+			extractDefSetList(pkgs),
 			searcherOptions()...,
 		)
-		writer = newWriter()
+		writer = newWriter(pkgs)
 	)
+	write(profiler, searcher, writer)
+}
+
+func write(profiler profile.Profiler, searcher search.UseSearcher, writer display.Writer) {
 	logger.Infof("Search and write")
 	for result := range searcher.Search() {
 		fail(writer.Write(result))
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/graph"
+)
+
+var (
+	rankTop    = flag.Int("rank.top", 10, "Number of definitions displayed per metric in rank.")
+	rankMetric = flag.String("rank.metric", "", "Comma separated metrics displayed in rank. All metrics if empty.")
+)
+
+func rankMetrics() []graph.Metric {
+	if *rankMetric == "" {
+		return nil
+	}
+	var metrics []graph.Metric
+	for _, x := range strings.Split(*rankMetric, ",") {
+		metrics = append(metrics, parseMetric(x))
+	}
+	return metrics
+}
+
+func runRank() {
+	profiler := newProfiler()
+	profiler.Init()
+	pkgs := loadPackages()
+	profiler.PkgLoaded(pkgs)
+	var (
+		searcher = newSearcher(
+			pkgs,
+			extractDefSetList(pkgs),
+			searcherOptions()...,
+		)
+		writer = display.NewRankWriter(os.Stdout, *rankTop, rankMetrics()...)
+	)
+	write(profiler, searcher, writer)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display/jsonify"
+	"github.com/berquerant/gotypegraph/logger"
+	"github.com/berquerant/gotypegraph/search"
+)
+
+// readUses reads the uses from the files or stdin if no files.
+func readUses() []search.Use {
+	var r io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		readers := make([]io.Reader, flag.NArg())
+		for i, name := range flag.Args() {
+			f, err := os.Open(name)
+			fail(err)
+			defer f.Close()
+			readers[i] = f
+		}
+		r = io.MultiReader(readers...)
+	}
+	logger.Infof("Read uses")
+	uses, err := jsonify.NewReader(r).Read()
+	fail(err)
+	logger.Infof("%d uses read", len(uses))
+	return uses
+}
+
+func runRender() {
+	// the restored objects have no types to show
+	if *statBy == "module" || *statBy == "type" {
+		fail(fmt.Errorf("render does not support stat.by %s", *statBy))
+	}
+	var (
+		uses   = readUses()
+		filter = search.NewUseFilter(uses, searcherOptions()...)
+		writer = newWriter(nil)
+		n      int
+	)
+	logger.Infof("Write")
+	for _, u := range uses {
+		if !filter(u) {
+			continue
+		}
+		fail(writer.Write(u))
+		n++
+	}
+	logger.Infof("Flush %d uses", n)
+	fail(writer.Flush())
+}
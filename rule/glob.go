@@ -0,0 +1,71 @@
+package rule
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Glob is a pattern of slash separated paths.
+//
+// "*" matches any sequence of non-slash characters,
+// "?" matches any non-slash character,
+// "**" matches any sequence of characters including slashes.
+// A leading "**/" and a trailing "/**" also match nothing,
+// e.g. "a/**" matches "a", "a/b" and "a/b/c".
+type Glob interface {
+	Pattern() string
+	Match(v string) bool
+}
+
+func NewGlob(pattern string) (Glob, error) {
+	re, err := regexp.Compile(globToRegexp(pattern))
+	if err != nil {
+		return nil, err
+	}
+	return &glob{
+		pattern: pattern,
+		re:      re,
+	}, nil
+}
+
+type glob struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func (s *glob) Pattern() string     { return s.pattern }
+func (s *glob) Match(v string) bool { return s.re.MatchString(v) }
+
+func globToRegexp(pattern string) string {
+	var (
+		b      strings.Builder
+		prefix string
+		suffix string
+	)
+	if strings.HasPrefix(pattern, "**/") {
+		pattern = pattern[3:]
+		prefix = "(.*/)?"
+	}
+	if strings.HasSuffix(pattern, "/**") {
+		pattern = pattern[:len(pattern)-3]
+		suffix = "(/.*)?"
+	}
+	b.WriteString("^" + prefix)
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString(suffix + "$")
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/rule"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlob(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		v       string
+		want    bool
+	}{
+		{pattern: "a/b", v: "a/b", want: true},
+		{pattern: "a/b", v: "a/bc"},
+		{pattern: "a/*", v: "a/b", want: true},
+		{pattern: "a/*", v: "a/b/c"},
+		{pattern: "a/?", v: "a/b", want: true},
+		{pattern: "a/?", v: "a/bc"},
+		{pattern: "a/**", v: "a", want: true},
+		{pattern: "a/**", v: "a/b/c", want: true},
+		{pattern: "a/**", v: "ab"},
+		{pattern: "**/domain/**", v: "example.com/app/domain", want: true},
+		{pattern: "**/domain/**", v: "example.com/app/domain/user", want: true},
+		{pattern: "**/domain/**", v: "domain", want: true},
+		{pattern: "**/domain/**", v: "example.com/app/subdomain"},
+		{pattern: "a/**/z", v: "a/b/c/z", want: true},
+		{pattern: "example.com/*", v: "exampleXcom/a"},
+		{pattern: "New*", v: "NewX", want: true},
+		{pattern: "X.*", v: "X.Method", want: true},
+	} {
+		tc := tc
+		t.Run(tc.pattern+" "+tc.v, func(t *testing.T) {
+			g, err := rule.NewGlob(tc.pattern)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, g.Match(tc.v))
+		})
+	}
+}
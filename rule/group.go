@@ -0,0 +1,122 @@
+package rule
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// GroupConfig is the groups file.
+	//
+	//   default: other
+	//   groups:
+	//     - name: platform
+	//       pkg: ["example.com/app/internal/**", "example.com/app/pkg/**"]
+	//     - name: billing
+	//       regexp: ^example\.com/app/(billing|invoice)
+	GroupConfig struct {
+		// Default is the group of the packages that match no groups.
+		// The package path is the group if empty.
+		Default string   `yaml:"default"`
+		Groups  []*Group `yaml:"groups"`
+	}
+
+	// Group selects the packages whose path matches one of Pkg or Regexp.
+	Group struct {
+		Name   string   `yaml:"name"`
+		Pkg    Patterns `yaml:"pkg"`
+		Regexp Patterns `yaml:"regexp"`
+	}
+
+	// GroupMapper maps a package to a group, the first group that matches wins.
+	GroupMapper interface {
+		Group(pkgPath string) string
+	}
+)
+
+// ParseGroups reads the groups file.
+func ParseGroups(r io.Reader) (GroupMapper, error) {
+	var config GroupConfig
+	if err := yaml.NewDecoder(r).Decode(&config); err != nil {
+		return nil, fmt.Errorf("parse groups: %w", err)
+	}
+	return NewGroupMapper(&config)
+}
+
+func NewGroupMapper(config *GroupConfig) (GroupMapper, error) {
+	groups := make([]*compiledGroup, len(config.Groups))
+	for i, g := range config.Groups {
+		if g.Name == "" {
+			return nil, fmt.Errorf("group[%d]: no name", i)
+		}
+		x, err := compileGroup(g)
+		if err != nil {
+			return nil, fmt.Errorf("group[%d] %s: %w", i, g.Name, err)
+		}
+		groups[i] = x
+	}
+	return &groupMapper{
+		defaultGroup: config.Default,
+		groups:       groups,
+	}, nil
+}
+
+type groupMapper struct {
+	defaultGroup string
+	groups       []*compiledGroup
+}
+
+func (s *groupMapper) Group(pkgPath string) string {
+	for _, g := range s.groups {
+		if g.match(pkgPath) {
+			return g.name
+		}
+	}
+	if s.defaultGroup != "" {
+		return s.defaultGroup
+	}
+	return pkgPath
+}
+
+type compiledGroup struct {
+	name    string
+	globs   []Glob
+	regexps []*regexp.Regexp
+}
+
+func compileGroup(g *Group) (*compiledGroup, error) {
+	globs, err := compilePatterns(g.Pkg)
+	if err != nil {
+		return nil, fmt.Errorf("pkg: %w", err)
+	}
+	regexps := make([]*regexp.Regexp, len(g.Regexp))
+	for i, p := range g.Regexp {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("regexp: %w", err)
+		}
+		regexps[i] = re
+	}
+	return &compiledGroup{
+		name:    g.Name,
+		globs:   globs,
+		regexps: regexps,
+	}, nil
+}
+
+func (s *compiledGroup) match(pkgPath string) bool {
+	for _, g := range s.globs {
+		if g.Match(pkgPath) {
+			return true
+		}
+	}
+	for _, re := range s.regexps {
+		if re.MatchString(pkgPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package rule_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/rule"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupMapper(t *testing.T) {
+	const src = `default: other
+groups:
+  - name: platform
+    pkg: ["app/internal/**", "app/pkg/**"]
+  - name: billing
+    regexp: ^app/(billing|invoice)
+  - name: all
+    pkg: "app/**"
+`
+	mapper, err := rule.ParseGroups(strings.NewReader(src))
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, tc := range []struct {
+		pkg  string
+		want string
+	}{
+		{pkg: "app/internal/db", want: "platform"},
+		{pkg: "app/pkg", want: "platform"},
+		{pkg: "app/billing/api", want: "billing"},
+		{pkg: "app/invoice", want: "billing"},
+		{pkg: "app/cmd", want: "all"},
+		{pkg: "fmt", want: "other"},
+	} {
+		assert.Equal(t, tc.want, mapper.Group(tc.pkg), tc.pkg)
+	}
+
+	t.Run("no default", func(t *testing.T) {
+		mapper, err := rule.ParseGroups(strings.NewReader(`groups: [{name: a, pkg: a}]`))
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, "a", mapper.Group("a"))
+		assert.Equal(t, "b", mapper.Group("b"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := rule.ParseGroups(strings.NewReader(`groups: [{name: a, regexp: "("}]`))
+		assert.NotNil(t, err)
+		_, err = rule.ParseGroups(strings.NewReader(`groups: [{pkg: a}]`))
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,216 @@
+package rule
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/berquerant/gotypegraph/search"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Config is the rules file.
+	//
+	//   rules:
+	//     - name: only cmd may use wire
+	//       action: allow
+	//       from:
+	//         pkg: example.com/app/cmd/**
+	//       to:
+	//         pkg: example.com/app/internal/wire/**
+	//     - name: no wire
+	//       action: deny
+	//       to:
+	//         pkg: example.com/app/internal/wire/**
+	Config struct {
+		Rules []*Rule `yaml:"rules"`
+	}
+
+	// Rule selects the dependencies from a node to a node.
+	// An empty selector selects all the nodes.
+	Rule struct {
+		Name   string   `yaml:"name"`
+		Action Action   `yaml:"action"`
+		From   Selector `yaml:"from"`
+		To     Selector `yaml:"to"`
+	}
+
+	// Selector selects the nodes whose package path matches one of Pkg
+	// and whose name matches one of Name.
+	// The name of a method or a field is "Recv.Name".
+	Selector struct {
+		Pkg  Patterns `yaml:"pkg"`
+		Name Patterns `yaml:"name"`
+	}
+
+	// Patterns is a list of globs, a single glob is also accepted.
+	Patterns []string
+
+	Action string
+)
+
+const (
+	AllowAction Action = "allow"
+	DenyAction  Action = "deny"
+)
+
+func (s *Patterns) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*s = Patterns{value.Value}
+		return nil
+	}
+	var v []string
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+	*s = Patterns(v)
+	return nil
+}
+
+type (
+	// Target is a node to be selected by rules.
+	Target struct {
+		Pkg  string
+		Name string
+	}
+
+	// RuleSet evaluates the rules in order.
+	RuleSet interface {
+		// Evaluate returns the first rule that matches the dependency.
+		Evaluate(ref, def *Target) (*Rule, bool)
+		// Denied returns the first rule if the rule denies the dependency.
+		Denied(ref, def *Target) (*Rule, bool)
+	}
+)
+
+func NewTarget(node search.Node) *Target {
+	name := node.Name()
+	if recv := node.RecvString(search.WithNodeRawRecv(true)); recv != "" {
+		name = fmt.Sprintf("%s.%s", recv, name)
+	}
+	return &Target{
+		Pkg:  node.Pkg().Path(),
+		Name: name,
+	}
+}
+
+// Parse reads the rules file.
+func Parse(r io.Reader) (RuleSet, error) {
+	var config Config
+	if err := yaml.NewDecoder(r).Decode(&config); err != nil {
+		return nil, fmt.Errorf("parse rules: %w", err)
+	}
+	return NewRuleSet(config.Rules)
+}
+
+func NewRuleSet(rules []*Rule) (RuleSet, error) {
+	compiled := make([]*compiledRule, len(rules))
+	for i, r := range rules {
+		x, err := compileRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("rule[%d] %s: %w", i, r.Name, err)
+		}
+		compiled[i] = x
+	}
+	return &ruleSet{
+		rules: compiled,
+	}, nil
+}
+
+type ruleSet struct {
+	rules []*compiledRule
+}
+
+func (s *ruleSet) Evaluate(ref, def *Target) (*Rule, bool) {
+	for _, r := range s.rules {
+		if r.from.match(ref) && r.to.match(def) {
+			return r.rule, true
+		}
+	}
+	return nil, false
+}
+
+func (s *ruleSet) Denied(ref, def *Target) (*Rule, bool) {
+	if r, ok := s.Evaluate(ref, def); ok && r.Action == DenyAction {
+		return r, true
+	}
+	return nil, false
+}
+
+type (
+	compiledRule struct {
+		rule *Rule
+		from *compiledSelector
+		to   *compiledSelector
+	}
+
+	compiledSelector struct {
+		pkg  []Glob
+		name []Glob
+	}
+)
+
+func compileRule(r *Rule) (*compiledRule, error) {
+	switch r.Action {
+	case AllowAction, DenyAction:
+	default:
+		return nil, fmt.Errorf("unknown action %q", r.Action)
+	}
+	from, err := compileSelector(r.From)
+	if err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+	to, err := compileSelector(r.To)
+	if err != nil {
+		return nil, fmt.Errorf("to: %w", err)
+	}
+	return &compiledRule{
+		rule: r,
+		from: from,
+		to:   to,
+	}, nil
+}
+
+func compileSelector(s Selector) (*compiledSelector, error) {
+	pkg, err := compilePatterns(s.Pkg)
+	if err != nil {
+		return nil, fmt.Errorf("pkg: %w", err)
+	}
+	name, err := compilePatterns(s.Name)
+	if err != nil {
+		return nil, fmt.Errorf("name: %w", err)
+	}
+	return &compiledSelector{
+		pkg:  pkg,
+		name: name,
+	}, nil
+}
+
+func compilePatterns(patterns Patterns) ([]Glob, error) {
+	globs := make([]Glob, len(patterns))
+	for i, p := range patterns {
+		g, err := NewGlob(p)
+		if err != nil {
+			return nil, err
+		}
+		globs[i] = g
+	}
+	return globs, nil
+}
+
+func (s *compiledSelector) match(tgt *Target) bool {
+	return matchAny(s.pkg, tgt.Pkg) && matchAny(s.name, tgt.Name)
+}
+
+// matchAny returns true if globs is empty or one of the globs matches v.
+func matchAny(globs []Glob, v string) bool {
+	if len(globs) == 0 {
+		return true
+	}
+	for _, g := range globs {
+		if g.Match(v) {
+			return true
+		}
+	}
+	return false
+}
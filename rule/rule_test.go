@@ -0,0 +1,119 @@
+package rule_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/rule"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleSet(t *testing.T) {
+	const src = `rules:
+  - name: domain must not depend on infra
+    action: deny
+    from:
+      pkg: "**/domain/**"
+    to:
+      pkg: "**/infra/**"
+  - name: only cmd may use wire
+    action: allow
+    from:
+      pkg: ["**/cmd/**"]
+    to:
+      pkg: "**/internal/wire/**"
+  - name: no wire
+    action: deny
+    to:
+      pkg: "**/internal/wire/**"
+  - name: no unsafe builders
+    action: deny
+    to:
+      pkg: app/unsafe
+      name: ["Builder.*", "NewBuilder"]
+`
+	rules, err := rule.Parse(strings.NewReader(src))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	for _, tc := range []struct {
+		title    string
+		ref      rule.Target
+		def      rule.Target
+		wantRule string
+		denied   bool
+	}{
+		{
+			title:    "domain to infra",
+			ref:      rule.Target{Pkg: "app/domain/user", Name: "User"},
+			def:      rule.Target{Pkg: "app/infra/db", Name: "Conn"},
+			wantRule: "domain must not depend on infra",
+			denied:   true,
+		},
+		{
+			title: "infra to domain",
+			ref:   rule.Target{Pkg: "app/infra/db", Name: "Conn"},
+			def:   rule.Target{Pkg: "app/domain/user", Name: "User"},
+		},
+		{
+			title:    "cmd to wire",
+			ref:      rule.Target{Pkg: "app/cmd/server", Name: "main"},
+			def:      rule.Target{Pkg: "app/internal/wire", Name: "Build"},
+			wantRule: "only cmd may use wire",
+		},
+		{
+			title:    "usecase to wire",
+			ref:      rule.Target{Pkg: "app/usecase", Name: "Run"},
+			def:      rule.Target{Pkg: "app/internal/wire", Name: "Build"},
+			wantRule: "no wire",
+			denied:   true,
+		},
+		{
+			title:    "method name",
+			ref:      rule.Target{Pkg: "app/usecase", Name: "Run"},
+			def:      rule.Target{Pkg: "app/unsafe", Name: "Builder.Build"},
+			wantRule: "no unsafe builders",
+			denied:   true,
+		},
+		{
+			title: "other name",
+			ref:   rule.Target{Pkg: "app/usecase", Name: "Run"},
+			def:   rule.Target{Pkg: "app/unsafe", Name: "Pointer"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			r, ok := rules.Evaluate(&tc.ref, &tc.def)
+			assert.Equal(t, tc.wantRule != "", ok)
+			if ok {
+				assert.Equal(t, tc.wantRule, r.Name)
+			}
+			_, denied := rules.Denied(&tc.ref, &tc.def)
+			assert.Equal(t, tc.denied, denied)
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		src   string
+	}{
+		{
+			title: "unknown action",
+			src: `rules:
+  - action: reject`,
+		},
+		{
+			title: "invalid yaml",
+			src:   `rules: [`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			_, err := rule.Parse(strings.NewReader(tc.src))
+			assert.NotNil(t, err)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/berquerant/gotypegraph/display"
+)
+
+func runSchema() {
+	fmt.Print(display.JSONStatSchema)
+}
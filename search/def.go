@@ -134,3 +134,50 @@ type defSet struct {
 
 func (s *defSet) Pkg() *packages.Package { return s.pkg }
 func (s *defSet) Defs() []Def            { return s.defs }
+
+type (
+	// DefNodeExtractor lists the top level definitions of a package as nodes.
+	DefNodeExtractor interface {
+		Extract(defSet DefSet) []DefNode
+	}
+)
+
+func NewDefNodeExtractor(objExtractor ObjExtractor) DefNodeExtractor {
+	return &defNodeExtractor{
+		objExtractor: objExtractor,
+	}
+}
+
+type defNodeExtractor struct {
+	objExtractor ObjExtractor
+}
+
+func (s *defNodeExtractor) Extract(defSet DefSet) []DefNode {
+	var (
+		pkg   = defSet.Pkg()
+		nodes []DefNode
+	)
+	add := func(ident *ast.Ident) {
+		if ident == nil || ident.Name == "_" {
+			return
+		}
+		if obj, ok := s.objExtractor.Extract(pkg, ident); ok && obj != nil {
+			nodes = append(nodes, NewDefNode(NewPkg(pkg), obj, &NodeInfo{}))
+		}
+	}
+	for _, def := range defSet.Defs() {
+		for _, fd := range def.FuncDecls() {
+			add(fd.Name)
+		}
+		for _, ts := range def.TypeSpecs() {
+			add(ts.Name)
+		}
+		for _, vs := range def.ValueSpecs() {
+			for _, nm := range vs.Names {
+				add(nm)
+			}
+		}
+	}
+	logger.Verbosef("[DefNodeExtractor] %s (%s) %d nodes", pkg.Name, pkg.ID, len(nodes))
+	return nodes
+}
@@ -4,10 +4,12 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"testing"
 
 	"github.com/berquerant/gotypegraph/search"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/packages"
 )
 
 type defExtractorTestcase struct {
@@ -158,3 +160,55 @@ func (*Empty) String() string {return "null"}`,
 		t.Run(tc.title, tc.test)
 	}
 }
+
+func TestDefNodeExtractor(t *testing.T) {
+	const src = `package testpkg
+func F() {}
+type T struct{}
+func (T) M() {}
+var V1, _ = 1, 2
+const C = 0`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if !assert.Nil(t, err) {
+		return
+	}
+	info := types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	var conf types.Config
+	_, err = conf.Check("testpkg", fset, []*ast.File{f}, &info)
+	if !assert.Nil(t, err) {
+		return
+	}
+	pkg := &packages.Package{
+		ID:        "pkgid",
+		Name:      "testpkg",
+		PkgPath:   "testpkg",
+		Syntax:    []*ast.File{f},
+		Fset:      fset,
+		TypesInfo: &info,
+	}
+	defSet := search.NewDefSetExtractor(search.NewDefExtractor()).Extract(pkg)
+	got := search.NewDefNodeExtractor(search.NewObjExtractor()).Extract(defSet)
+
+	want := []struct {
+		name     string
+		nodeType search.NodeType
+	}{
+		{name: "F", nodeType: search.FuncNodeType},
+		{name: "M", nodeType: search.MethodNodeType},
+		{name: "T", nodeType: search.TypeNodeType},
+		{name: "V1", nodeType: search.VarNodeType},
+		{name: "C", nodeType: search.ConstNodeType},
+	}
+	if !assert.Equal(t, len(want), len(got)) {
+		return
+	}
+	for i, w := range want {
+		assert.Equal(t, w.name, got[i].Name())
+		assert.Equal(t, w.nodeType, got[i].Type())
+		assert.Equal(t, "testpkg", got[i].Pkg().Path())
+	}
+}
@@ -1,7 +1,9 @@
 package search
 
 import (
+	"go/ast"
 	"go/token"
+	"go/types"
 
 	"github.com/berquerant/gotypegraph/logger"
 	"github.com/berquerant/gotypegraph/util"
@@ -97,3 +99,46 @@ func DefSetFilter(setList []DefSet) Filter {
 		return ok && p[obj.Pos()]
 	}
 }
+
+// FieldFilter selects a target that is a field of the struct types in the defs.
+func FieldFilter(setList []DefSet) Filter {
+	type posRange struct {
+		start token.Pos
+		end   token.Pos
+	}
+	pkgSet := make(map[string][]posRange, len(setList))
+	for _, defSet := range setList {
+		var (
+			path   = defSet.Pkg().PkgPath
+			ranges []posRange
+		)
+		for _, def := range defSet.Defs() {
+			for _, ts := range def.TypeSpecs() {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					logger.Debugf("[FieldFilter][init][%s] %s from %d to %d", path, ts.Name, st.Pos(), st.End())
+					ranges = append(ranges, posRange{
+						start: st.Pos(),
+						end:   st.End(),
+					})
+				}
+			}
+		}
+		pkgSet[path] = ranges
+	}
+
+	return func(tgt Target) bool {
+		obj := tgt.Obj()
+		if obj == nil || obj.Pkg() == nil {
+			return false
+		}
+		if v, ok := obj.(*types.Var); !ok || !v.IsField() {
+			return false
+		}
+		for _, r := range pkgSet[obj.Pkg().Path()] {
+			if r.start <= obj.Pos() && obj.Pos() < r.end {
+				return true
+			}
+		}
+		return false
+	}
+}
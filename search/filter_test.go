@@ -1,6 +1,7 @@
 package search_test
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
@@ -190,3 +191,58 @@ func TestFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldFilter(t *testing.T) {
+	const src = `package testpkg
+type X struct {
+  a int
+  Y
+}
+type Y struct {
+  b int
+}
+func F(x X) int {
+  var z struct{ c int }
+  return x.a + x.b + z.c
+}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if !assert.Nil(t, err) {
+		return
+	}
+	info := types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	var conf types.Config
+	if _, err := conf.Check("testpkg", fset, []*ast.File{f}, &info); !assert.Nil(t, err) {
+		return
+	}
+	set := search.NewDefSetExtractor(search.NewDefExtractor()).Extract(&packages.Package{
+		ID:      "testpkgid",
+		Name:    "testpkg",
+		PkgPath: "testpkg",
+		Syntax:  []*ast.File{f},
+		Fset:    fset,
+	})
+	filter := search.FieldFilter([]search.DefSet{set})
+
+	got := map[string]bool{}
+	for ident, obj := range info.Defs {
+		if obj != nil {
+			key := fmt.Sprintf("%s:%d", ident.Name, fset.Position(ident.Pos()).Line)
+			got[key] = filter(search.NewTarget(ident, obj))
+		}
+	}
+	assert.Equal(t, map[string]bool{
+		"X:2":  false,
+		"a:3":  true,
+		"Y:4":  true, // embedded field
+		"Y:6":  false,
+		"b:7":  true,
+		"F:9":  false,
+		"x:9":  false,
+		"z:10": false,
+		"c:10": false,
+	}, got)
+}
@@ -67,42 +67,96 @@ func NewUseSearcher(
 		logger.Verbosef("[UseSearcher] with pkg %s (%s)", pkg.Name, pkg.PkgPath)
 		pkgSet[pkg.PkgPath] = pkg
 	}
+	filter := config.filter(defSetFilter, pkgs)
+	if config.ignorePkgSelfloop {
+		logger.Debugf("[UseSearcher] ignore pkg self loop")
+	}
+	if config.ignoreUseSelfloop {
+		logger.Debugf("[UseSearcher] ignore use self loop")
+	}
+
+	return &useSearcher{
+		pkgSet:        pkgSet,
+		objExtractor:  objExtractor,
+		refSearcher:   refSearcher,
+		tgtExtractor:  tgtExtractor,
+		fieldSearcher: fieldSearcher,
+		filter:        filter,
+		conf:          &config,
+	}
+}
+
+// filter composes the filters of the targets on the defs of the loaded packages.
+func (c *UseSearcherConfig) filter(defSetFilter Filter, pkgs []*packages.Package) Filter {
 	filter := defSetFilter
-	if !config.searchPrivate {
+	if !c.searchPrivate {
 		logger.Debugf("[UseSearcher] use exported filter")
 		filter = filter.And(ExportedFilter)
 	}
-	if config.searchForeign {
+	if c.searchForeign {
 		logger.Debugf("[UseSearcher] use foreign filter")
 		filter = filter.Or(OtherPkgFilter(pkgs))
 	}
-	if config.searchUniverse {
+	if c.searchUniverse {
 		logger.Debugf("[UseSearcher] use universe filter")
 		filter = filter.Or(UniverseFilter)
 	}
-	if config.pkgNameRegexp != nil {
+	if c.pkgNameRegexp != nil {
 		logger.Debugf("[UseSearcher] use pkg name filter")
-		filter = filter.And(PkgNameFilter(config.pkgNameRegexp))
+		filter = filter.And(PkgNameFilter(c.pkgNameRegexp))
 	}
-	if config.objNameRegexp != nil {
+	if c.objNameRegexp != nil {
 		logger.Debugf("[UseSearcher] use obj name filter")
-		filter = filter.And(ObjectNameFilter(config.objNameRegexp))
+		filter = filter.And(ObjectNameFilter(c.objNameRegexp))
 	}
-	if config.ignorePkgSelfloop {
-		logger.Debugf("[UseSearcher] ignore pkg self loop")
+	return filter
+}
+
+// NewUseFilter returns the filter that selects the uses as the searcher with the same options does.
+// The loaded packages are those of the uses.
+func NewUseFilter(uses []Use, opt ...UseSearcherOption) func(Use) bool {
+	var config UseSearcherConfig
+	for _, x := range opt {
+		x(&config)
 	}
-	if config.ignoreUseSelfloop {
-		logger.Debugf("[UseSearcher] ignore use self loop")
+
+	pkgSet := make(map[string]*packages.Package)
+	for _, u := range uses {
+		for _, p := range []Pkg{u.Ref().Pkg(), u.Def().Pkg()} {
+			if pkg := p.Pkg(); pkg != nil {
+				pkgSet[pkg.PkgPath] = pkg
+			}
+		}
+	}
+	pkgs := make([]*packages.Package, 0, len(pkgSet))
+	for _, pkg := range pkgSet {
+		pkgs = append(pkgs, pkg)
+	}
+	loadedFilter := Filter(func(tgt Target) bool {
+		obj := tgt.Obj()
+		if obj == nil || obj.Pkg() == nil {
+			return false
+		}
+		_, ok := pkgSet[obj.Pkg().Path()]
+		return ok
+	})
+	s := &useSearcher{
+		pkgSet: pkgSet,
+		filter: config.filter(loadedFilter, pkgs),
+		conf:   &config,
 	}
 
-	return &useSearcher{
-		pkgSet:        pkgSet,
-		objExtractor:  objExtractor,
-		refSearcher:   refSearcher,
-		tgtExtractor:  tgtExtractor,
-		fieldSearcher: fieldSearcher,
-		filter:        filter,
-		conf:          &config,
+	return func(u Use) bool {
+		var (
+			ref = u.Ref()
+			def = u.Def()
+			pkg = ref.Pkg().Pkg()
+		)
+		return pkg != nil &&
+			s.selectPkg(pkg) &&
+			s.filter(NewTarget(ref.Ident(), def.Obj())) &&
+			!s.ignorePkgSelfloop(pkg, def.Obj()) &&
+			!s.ignoreUseSelfloop(ref.Obj(), def.Obj())
 	}
 }
 
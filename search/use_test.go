@@ -1,12 +1,17 @@
 package search_test
 
 import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
 	"sort"
 	"strconv"
 	"testing"
 
 	"github.com/berquerant/gotypegraph/load"
 	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/util"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/tools/go/packages"
 )
@@ -298,3 +303,125 @@ func doUseSearch(pkgs []*packages.Package, opt ...search.UseSearcherOption) []se
 	})
 	return got
 }
+
+func TestUseFilter(t *testing.T) {
+	var (
+		fset  = token.NewFileSet()
+		file  = fset.AddFile("a.go", -1, 100)
+		tpkgA = types.NewPackage("example.com/a", "a")
+		tpkgB = types.NewPackage("example.com/b", "b")
+		pkgA  = search.NewPkg(&packages.Package{Name: "a", PkgPath: "example.com/a", Fset: fset, Types: tpkgA})
+		pkgB  = search.NewPkg(&packages.Package{Name: "b", PkgPath: "example.com/b", Fset: fset, Types: tpkgB})
+		sig   = types.NewSignature(nil, nil, nil, false)
+
+		f          = types.NewFunc(file.Pos(1), tpkgA, "F", sig)
+		private    = types.NewFunc(file.Pos(2), tpkgA, "private", sig)
+		g          = types.NewFunc(file.Pos(3), tpkgB, "G", sig)
+		fmtPrintln = types.NewFunc(token.NoPos, types.NewPackage("fmt", "fmt"), "Println", sig)
+		newUse     = func(refPkg search.Pkg, ref types.Object, pos int, defPkg search.Pkg, def types.Object) search.Use {
+			ident := ast.NewIdent(def.Name())
+			ident.NamePos = file.Pos(pos)
+			return search.NewUse(
+				search.NewRefNode(refPkg, ref, &search.NodeInfo{ValueSpecIndex: -1}, nil, ident),
+				search.NewDefNode(defPkg, def, &search.NodeInfo{}),
+			)
+		}
+
+		uses = []search.Use{
+			newUse(pkgA, f, 10, pkgA, private),
+			newUse(pkgA, f, 11, pkgB, g),
+			newUse(pkgB, g, 12, pkgA, f),
+			newUse(pkgA, f, 13, search.NewPkgWithName("fmt", "fmt"), fmtPrintln),
+			newUse(pkgA, f, 14, search.NewBuiltinPkg(), types.Universe.Lookup("len")),
+			newUse(pkgA, f, 15, pkgA, f),
+		}
+	)
+
+	for _, tc := range []struct {
+		title string
+		opt   []search.UseSearcherOption
+		want  []int
+	}{
+		{
+			title: "exported",
+			want:  []int{1, 2, 5},
+		},
+		{
+			title: "private",
+			opt: []search.UseSearcherOption{
+				search.WithUseSearcherSearchPrivate(true),
+			},
+			want: []int{0, 1, 2, 5},
+		},
+		{
+			title: "foreign",
+			opt: []search.UseSearcherOption{
+				search.WithUseSearcherSearchForeign(true),
+			},
+			want: []int{1, 2, 3, 5},
+		},
+		{
+			title: "universe",
+			opt: []search.UseSearcherOption{
+				search.WithUseSearcherSearchUniverse(true),
+			},
+			want: []int{1, 2, 4, 5},
+		},
+		{
+			title: "universe has no pkg name",
+			opt: []search.UseSearcherOption{
+				search.WithUseSearcherSearchUniverse(true),
+				search.WithUseSearcherPkgNameRegexp(util.NewRegexpPair(nil, nil)),
+			},
+			want: []int{1, 2, 5},
+		},
+		{
+			title: "pkg name",
+			opt: []search.UseSearcherOption{
+				search.WithUseSearcherSearchPrivate(true),
+				search.WithUseSearcherPkgNameRegexp(util.NewRegexpPair(regexp.MustCompile(`^a$`), nil)),
+			},
+			want: []int{0, 5},
+		},
+		{
+			title: "obj name",
+			opt: []search.UseSearcherOption{
+				search.WithUseSearcherSearchForeign(true),
+				search.WithUseSearcherObjNameRegexp(util.NewRegexpPair(nil, regexp.MustCompile(`^F$`))),
+			},
+			want: []int{1, 3},
+		},
+		{
+			title: "ignore pkg selfloop",
+			opt: []search.UseSearcherOption{
+				search.WithUseSearcherSearchPrivate(true),
+				search.WithUseSearcherSearchForeign(true),
+				search.WithUseSearcherSearchUniverse(true),
+				search.WithUseSearcherIgnorePkgSelfloop(true),
+			},
+			want: []int{1, 2, 3, 4},
+		},
+		{
+			title: "ignore use selfloop",
+			opt: []search.UseSearcherOption{
+				search.WithUseSearcherSearchPrivate(true),
+				search.WithUseSearcherIgnoreUseSelfloop(true),
+			},
+			want: []int{0, 1, 2},
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			var (
+				filter = search.NewUseFilter(uses, tc.opt...)
+				got    []int
+			)
+			for i, u := range uses {
+				if filter(u) {
+					got = append(got, i)
+				}
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
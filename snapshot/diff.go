@@ -0,0 +1,77 @@
+package snapshot
+
+type (
+	// Diff is the difference of the dependencies from a snapshot to another.
+	Diff struct {
+		Pkgs  *EdgeSetDiff `json:"pkgs"`
+		Nodes *EdgeSetDiff `json:"nodes"`
+	}
+
+	EdgeSetDiff struct {
+		Added   []*Edge     `json:"added"`
+		Removed []*Edge     `json:"removed"`
+		Changed []*EdgeDiff `json:"changed"`
+	}
+
+	// EdgeDiff is an edge whose weight is changed.
+	EdgeDiff struct {
+		Ref    string `json:"ref"`
+		Def    string `json:"def"`
+		Before int    `json:"before"`
+		After  int    `json:"after"`
+	}
+)
+
+func NewDiff(before, after *Snapshot) *Diff {
+	return &Diff{
+		Pkgs:  NewEdgeSetDiff(before.Pkgs, after.Pkgs),
+		Nodes: NewEdgeSetDiff(before.Nodes, after.Nodes),
+	}
+}
+
+// IsEmpty returns true if no differences.
+func (s *EdgeSetDiff) IsEmpty() bool {
+	return len(s.Added) == 0 && len(s.Removed) == 0 && len(s.Changed) == 0
+}
+
+func NewEdgeSetDiff(before, after []*Edge) *EdgeSetDiff {
+	type key struct {
+		ref string
+		def string
+	}
+	var (
+		beforeSet = make(map[key]*Edge, len(before))
+		afterSet  = make(map[key]*Edge, len(after))
+		diff      = &EdgeSetDiff{
+			Added:   []*Edge{},
+			Removed: []*Edge{},
+			Changed: []*EdgeDiff{},
+		}
+	)
+	for _, x := range before {
+		beforeSet[key{x.Ref, x.Def}] = x
+	}
+	for _, x := range after {
+		afterSet[key{x.Ref, x.Def}] = x
+	}
+	for _, x := range SortEdges(append([]*Edge{}, after...)) {
+		b, found := beforeSet[key{x.Ref, x.Def}]
+		switch {
+		case !found:
+			diff.Added = append(diff.Added, x)
+		case b.Weight != x.Weight:
+			diff.Changed = append(diff.Changed, &EdgeDiff{
+				Ref:    x.Ref,
+				Def:    x.Def,
+				Before: b.Weight,
+				After:  x.Weight,
+			})
+		}
+	}
+	for _, x := range SortEdges(append([]*Edge{}, before...)) {
+		if _, found := afterSet[key{x.Ref, x.Def}]; !found {
+			diff.Removed = append(diff.Removed, x)
+		}
+	}
+	return diff
+}
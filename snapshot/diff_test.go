@@ -0,0 +1,41 @@
+package snapshot_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/snapshot"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEdgeSetDiff(t *testing.T) {
+	var (
+		before = []*snapshot.Edge{
+			{Ref: "a", Def: "b", Weight: 1},
+			{Ref: "a", Def: "c", Weight: 2},
+			{Ref: "b", Def: "c", Weight: 1},
+		}
+		after = []*snapshot.Edge{
+			{Ref: "c", Def: "d", Weight: 1},
+			{Ref: "a", Def: "c", Weight: 3},
+			{Ref: "b", Def: "c", Weight: 1},
+			{Ref: "a", Def: "d", Weight: 1},
+		}
+	)
+	got := snapshot.NewEdgeSetDiff(before, after)
+	assert.Equal(t, &snapshot.EdgeSetDiff{
+		Added: []*snapshot.Edge{
+			{Ref: "a", Def: "d", Weight: 1},
+			{Ref: "c", Def: "d", Weight: 1},
+		},
+		Removed: []*snapshot.Edge{
+			{Ref: "a", Def: "b", Weight: 1},
+		},
+		Changed: []*snapshot.EdgeDiff{
+			{Ref: "a", Def: "c", Before: 2, After: 3},
+		},
+	}, got)
+	assert.False(t, got.IsEmpty())
+	assert.True(t, snapshot.NewEdgeSetDiff(before, before).IsEmpty())
+	// inputs are not modified
+	assert.Equal(t, "c", after[0].Ref)
+}
@@ -0,0 +1,215 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/berquerant/gotypegraph/graph"
+)
+
+type (
+	// Gate detects the regressions of the dependencies of the packages from the baseline.
+	Gate interface {
+		Check(baseline, current *Snapshot) []*Violation
+	}
+
+	GateConfig struct {
+		edge     bool
+		cycle    bool
+		coupling bool
+	}
+
+	GateOption func(*GateConfig)
+
+	Violation struct {
+		Kind    ViolationKind `json:"kind"`
+		Message string        `json:"message"`
+	}
+
+	ViolationKind string
+)
+
+const (
+	// EdgeViolation is a new dependency between packages.
+	EdgeViolation ViolationKind = "edge"
+	// CycleViolation is a new cycle of packages.
+	CycleViolation ViolationKind = "cycle"
+	// CouplingViolation is an increase of the coupling metrics of a package.
+	CouplingViolation ViolationKind = "coupling"
+)
+
+func (s *Violation) String() string { return fmt.Sprintf("%s\t%s", s.Kind, s.Message) }
+
+// WithGateEdge detects new dependencies between packages.
+func WithGateEdge(v bool) GateOption {
+	return func(c *GateConfig) {
+		c.edge = v
+	}
+}
+
+// WithGateCycle detects new cycles of packages.
+func WithGateCycle(v bool) GateOption {
+	return func(c *GateConfig) {
+		c.cycle = v
+	}
+}
+
+// WithGateCoupling detects increases of the coupling metrics of packages.
+func WithGateCoupling(v bool) GateOption {
+	return func(c *GateConfig) {
+		c.coupling = v
+	}
+}
+
+func NewGate(opt ...GateOption) Gate {
+	config := GateConfig{
+		edge:     true,
+		cycle:    true,
+		coupling: true,
+	}
+	for _, x := range opt {
+		x(&config)
+	}
+	return &gate{
+		conf: &config,
+	}
+}
+
+type gate struct {
+	conf *GateConfig
+}
+
+func (s *gate) Check(baseline, current *Snapshot) []*Violation {
+	violations := []*Violation{}
+	if s.conf.edge {
+		violations = append(violations, s.checkEdge(baseline, current)...)
+	}
+	if s.conf.cycle {
+		violations = append(violations, s.checkCycle(baseline, current)...)
+	}
+	if s.conf.coupling {
+		violations = append(violations, s.checkCoupling(baseline, current)...)
+	}
+	return violations
+}
+
+func (*gate) checkEdge(baseline, current *Snapshot) []*Violation {
+	violations := []*Violation{}
+	for _, x := range NewEdgeSetDiff(baseline.Pkgs, current.Pkgs).Added {
+		if x.Ref == x.Def {
+			continue
+		}
+		violations = append(violations, &Violation{
+			Kind:    EdgeViolation,
+			Message: fmt.Sprintf("%s -> %s [%d]", x.Ref, x.Def, x.Weight),
+		})
+	}
+	return violations
+}
+
+// checkCycle reports the cycles that are not contained in any cycles of the baseline.
+func (*gate) checkCycle(baseline, current *Snapshot) []*Violation {
+	var (
+		violations = []*Violation{}
+		baseCycle  = map[string]int{} // pkg => index of cycle
+	)
+	for i, c := range graph.Cycles(NewPkgGraph(baseline)) {
+		for _, x := range c {
+			baseCycle[x] = i
+		}
+	}
+	for _, c := range graph.Cycles(NewPkgGraph(current)) {
+		contained := true
+		i, found := baseCycle[c[0]]
+		for _, x := range c {
+			if j, ok := baseCycle[x]; !found || !ok || i != j {
+				contained = false
+				break
+			}
+		}
+		if contained {
+			continue
+		}
+		violations = append(violations, &Violation{
+			Kind:    CycleViolation,
+			Message: strings.Join(c, " "),
+		})
+	}
+	return violations
+}
+
+func (*gate) checkCoupling(baseline, current *Snapshot) []*Violation {
+	var (
+		violations = []*Violation{}
+		base       = NewCouplings(baseline)
+		cur        = NewCouplings(current)
+		pkgs       = make([]string, 0, len(cur))
+	)
+	for x := range cur {
+		pkgs = append(pkgs, x)
+	}
+	sort.Strings(pkgs)
+	for _, pkg := range pkgs {
+		var (
+			c = cur[pkg]
+			b = base[pkg]
+		)
+		if b == nil {
+			b = &Coupling{}
+		}
+		if c.Afferent > b.Afferent {
+			violations = append(violations, &Violation{
+				Kind:    CouplingViolation,
+				Message: fmt.Sprintf("%s afferent %d -> %d", pkg, b.Afferent, c.Afferent),
+			})
+		}
+		if c.Efferent > b.Efferent {
+			violations = append(violations, &Violation{
+				Kind:    CouplingViolation,
+				Message: fmt.Sprintf("%s efferent %d -> %d", pkg, b.Efferent, c.Efferent),
+			})
+		}
+	}
+	return violations
+}
+
+// Coupling is the coupling metrics of a package.
+type Coupling struct {
+	// Afferent is the number of the other packages that depend on the package.
+	Afferent int
+	// Efferent is the number of the other packages that the package depends on.
+	Efferent int
+}
+
+// NewCouplings computes the coupling metrics by package.
+func NewCouplings(s *Snapshot) map[string]*Coupling {
+	d := map[string]*Coupling{}
+	get := func(pkg string) *Coupling {
+		if x, ok := d[pkg]; ok {
+			return x
+		}
+		x := &Coupling{}
+		d[pkg] = x
+		return x
+	}
+	for _, x := range s.Pkgs {
+		ref := get(x.Ref)
+		def := get(x.Def)
+		if x.Ref == x.Def {
+			continue
+		}
+		ref.Efferent++
+		def.Afferent++
+	}
+	return d
+}
+
+// NewPkgGraph converts the package dependencies into a graph.
+func NewPkgGraph(s *Snapshot) graph.Graph {
+	g := graph.New()
+	for _, x := range s.Pkgs {
+		g.AddEdge(x.Ref, x.Def, x.Weight)
+	}
+	return g
+}
@@ -0,0 +1,78 @@
+package snapshot_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/snapshot"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPkgSnapshot(edges ...*snapshot.Edge) *snapshot.Snapshot {
+	return &snapshot.Snapshot{
+		Version: snapshot.Version,
+		Pkgs:    edges,
+	}
+}
+
+func TestGate(t *testing.T) {
+	baseline := newPkgSnapshot(
+		&snapshot.Edge{Ref: "a", Def: "b", Weight: 1},
+		&snapshot.Edge{Ref: "b", Def: "c", Weight: 1},
+		&snapshot.Edge{Ref: "c", Def: "b", Weight: 1},
+	)
+
+	for _, tc := range []struct {
+		title   string
+		current *snapshot.Snapshot
+		opt     []snapshot.GateOption
+		want    []*snapshot.Violation
+	}{
+		{
+			title:   "no changes",
+			current: baseline,
+			want:    []*snapshot.Violation{},
+		},
+		{
+			title: "weight and self loop",
+			current: newPkgSnapshot(
+				&snapshot.Edge{Ref: "a", Def: "a", Weight: 1},
+				&snapshot.Edge{Ref: "a", Def: "b", Weight: 5},
+				&snapshot.Edge{Ref: "b", Def: "c", Weight: 1},
+			),
+			want: []*snapshot.Violation{},
+		},
+		{
+			title: "new edge and cycle",
+			current: newPkgSnapshot(
+				&snapshot.Edge{Ref: "a", Def: "b", Weight: 1},
+				&snapshot.Edge{Ref: "b", Def: "c", Weight: 1},
+				&snapshot.Edge{Ref: "c", Def: "b", Weight: 1},
+				&snapshot.Edge{Ref: "c", Def: "a", Weight: 2},
+			),
+			want: []*snapshot.Violation{
+				{Kind: snapshot.EdgeViolation, Message: "c -> a [2]"},
+				{Kind: snapshot.CycleViolation, Message: "a b c"},
+				{Kind: snapshot.CouplingViolation, Message: "a afferent 0 -> 1"},
+				{Kind: snapshot.CouplingViolation, Message: "c efferent 1 -> 2"},
+			},
+		},
+		{
+			title: "new edge without coupling",
+			current: newPkgSnapshot(
+				&snapshot.Edge{Ref: "a", Def: "b", Weight: 1},
+				&snapshot.Edge{Ref: "a", Def: "d", Weight: 1},
+			),
+			opt: []snapshot.GateOption{
+				snapshot.WithGateCoupling(false),
+			},
+			want: []*snapshot.Violation{
+				{Kind: snapshot.EdgeViolation, Message: "a -> d [1]"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			assert.Equal(t, tc.want, snapshot.NewGate(tc.opt...).Check(baseline, tc.current))
+		})
+	}
+}
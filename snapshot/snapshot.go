@@ -0,0 +1,127 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+)
+
+type (
+	// Snapshot is the aggregated dependencies.
+	// The edges are sorted by ref and def.
+	Snapshot struct {
+		Version int     `json:"version"`
+		Pkgs    []*Edge `json:"pkgs"`
+		Nodes   []*Edge `json:"nodes"`
+	}
+
+	// Edge is a dependency from ref to def.
+	// The id of a package is the path,
+	// the id of a node is "Path.Name" or "Path.Recv.Name".
+	Edge struct {
+		Ref    string `json:"ref"`
+		Def    string `json:"def"`
+		Weight int    `json:"weight"`
+	}
+
+	Builder interface {
+		Add(search.Use)
+		Build() *Snapshot
+	}
+)
+
+// Version is the version of the snapshot format.
+const Version = 1
+
+// Write writes the snapshot as indented JSON.
+// The output is stable for the same dependencies.
+func Write(w io.Writer, s *Snapshot) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if _, err := fmt.Fprintln(w, string(b)); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Read reads the snapshot written by Write.
+func Read(r io.Reader) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+	if s.Version != Version {
+		return nil, fmt.Errorf("read snapshot: unknown version %d", s.Version)
+	}
+	return &s, nil
+}
+
+// NodeID returns the id of the node that does not depend on the positions.
+func NodeID(node search.Node) string {
+	if recv := node.RecvString(search.WithNodeRawRecv(true)); recv != "" {
+		return fmt.Sprintf("%s.%s.%s", node.Pkg().Path(), recv, node.Name())
+	}
+	return fmt.Sprintf("%s.%s", node.Pkg().Path(), node.Name())
+}
+
+func NewBuilder() Builder {
+	return &builder{
+		pkgDepCalc:  stat.NewPkgDepCalculator(),
+		nodeDepCalc: stat.NewNodeDepCalculator(),
+	}
+}
+
+type builder struct {
+	pkgDepCalc  stat.PkgDepCalculator
+	nodeDepCalc stat.NodeDepCalculator
+}
+
+func (s *builder) Add(use search.Use) {
+	s.pkgDepCalc.Add(stat.NewPkg(use.Ref().Pkg()), stat.NewPkg(use.Def().Pkg()))
+	s.nodeDepCalc.Add(stat.NewNode(use.Ref()), stat.NewNode(use.Def()))
+}
+
+func (s *builder) Build() *Snapshot {
+	var (
+		pkgDeps  = s.pkgDepCalc.Result()
+		nodeDeps = s.nodeDepCalc.Result()
+		pkgs     = make([]*Edge, len(pkgDeps))
+		nodes    = make([]*Edge, len(nodeDeps))
+	)
+	for i, x := range pkgDeps {
+		pkgs[i] = &Edge{
+			Ref:    x.Ref().ID(),
+			Def:    x.Def().ID(),
+			Weight: x.Weight(),
+		}
+	}
+	for i, x := range nodeDeps {
+		nodes[i] = &Edge{
+			Ref:    NodeID(x.Ref().Node()),
+			Def:    NodeID(x.Def().Node()),
+			Weight: x.Weight(),
+		}
+	}
+	return &Snapshot{
+		Version: Version,
+		Pkgs:    SortEdges(pkgs),
+		Nodes:   SortEdges(nodes),
+	}
+}
+
+// SortEdges sorts the edges by ref and def.
+func SortEdges(edges []*Edge) []*Edge {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Ref != edges[j].Ref {
+			return edges[i].Ref < edges[j].Ref
+		}
+		return edges[i].Def < edges[j].Def
+	})
+	return edges
+}
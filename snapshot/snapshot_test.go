@@ -0,0 +1,49 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/snapshot"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteRead(t *testing.T) {
+	s := &snapshot.Snapshot{
+		Version: snapshot.Version,
+		Pkgs: []*snapshot.Edge{
+			{Ref: "a", Def: "b", Weight: 2},
+		},
+		Nodes: []*snapshot.Edge{
+			{Ref: "a.F", Def: "b.T.M", Weight: 2},
+		},
+	}
+	var b bytes.Buffer
+	assert.Nil(t, snapshot.Write(&b, s))
+	assert.Equal(t, `{
+  "version": 1,
+  "pkgs": [
+    {
+      "ref": "a",
+      "def": "b",
+      "weight": 2
+    }
+  ],
+  "nodes": [
+    {
+      "ref": "a.F",
+      "def": "b.T.M",
+      "weight": 2
+    }
+  ]
+}
+`, b.String())
+
+	got, err := snapshot.Read(&b)
+	assert.Nil(t, err)
+	assert.Equal(t, s, got)
+
+	_, err = snapshot.Read(strings.NewReader(`{"version":0}`))
+	assert.NotNil(t, err)
+}
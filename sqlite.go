@@ -0,0 +1,38 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/logger"
+	_ "modernc.org/sqlite"
+)
+
+var sqliteFile = flag.String("sqlite.file", "gotypegraph.db", "SQLite database file to write when type is sqlite, the tables are recreated.")
+
+// newSQLiteWriter returns a writer that writes into the SQLite database file.
+func newSQLiteWriter() display.Writer {
+	db, err := sql.Open("sqlite", *sqliteFile)
+	fail(err)
+	logger.Infof("Write %s", *sqliteFile)
+	return &dbWriter{
+		Writer: display.NewSQLiteWriter(db),
+		db:     db,
+	}
+}
+
+// dbWriter closes the database after flush.
+type dbWriter struct {
+	display.Writer
+	db *sql.DB
+}
+
+func (s *dbWriter) Flush() error {
+	err := s.Writer.Flush()
+	if cerr := s.db.Close(); cerr != nil && err == nil {
+		err = fmt.Errorf("close %s: %w", *sqliteFile, cerr)
+	}
+	return err
+}
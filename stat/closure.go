@@ -0,0 +1,185 @@
+package stat
+
+import (
+	"encoding/json"
+
+	"github.com/berquerant/gotypegraph/graph"
+)
+
+/* node transitive closures */
+
+type (
+	NodeClosureSet interface {
+		Closures() []NodeClosure
+		Get(Node) (NodeClosure, bool)
+	}
+	// NodeClosure is the sizes of the transitive closures of a node, excluding itself.
+	NodeClosure interface {
+		Node() Node
+		// Dependencies is the number of the nodes that the node depends on transitively.
+		Dependencies() int
+		// Dependents is the number of the nodes that depend on the node transitively.
+		Dependents() int
+	}
+	NodeClosureCalculator interface {
+		Add(ref, def Node)
+		Result() NodeClosureSet
+	}
+)
+
+type nodeClosure struct {
+	node    Node
+	closure *graph.Closure
+}
+
+func (s *nodeClosure) Node() Node        { return s.node }
+func (s *nodeClosure) Dependencies() int { return s.closure.Dependencies }
+func (s *nodeClosure) Dependents() int   { return s.closure.Dependents }
+func (s *nodeClosure) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"node":         s.node,
+		"dependencies": s.closure.Dependencies,
+		"dependents":   s.closure.Dependents,
+	})
+}
+
+type nodeClosureSet struct {
+	closures map[string]*nodeClosure
+}
+
+func (s *nodeClosureSet) MarshalJSON() ([]byte, error) { return json.Marshal(s.closures) }
+func (s *nodeClosureSet) Closures() []NodeClosure {
+	var (
+		i        int
+		closures = make([]NodeClosure, len(s.closures))
+	)
+	for _, x := range s.closures {
+		closures[i] = x
+		i++
+	}
+	return closures
+}
+func (s *nodeClosureSet) Get(node Node) (NodeClosure, bool) {
+	x, ok := s.closures[node.ID()]
+	return x, ok
+}
+
+func NewNodeClosureCalculator() NodeClosureCalculator {
+	return &nodeClosureCalculator{
+		nodes: map[string]Node{},
+		graph: graph.New(),
+	}
+}
+
+type nodeClosureCalculator struct {
+	nodes map[string]Node
+	graph graph.Graph
+}
+
+func (s *nodeClosureCalculator) Add(ref, def Node) {
+	s.nodes[ref.ID()] = ref
+	s.nodes[def.ID()] = def
+	s.graph.AddEdge(ref.ID(), def.ID(), 1)
+}
+
+func (s *nodeClosureCalculator) Result() NodeClosureSet {
+	d := map[string]*nodeClosure{}
+	for id, x := range graph.Closures(s.graph) {
+		d[id] = &nodeClosure{
+			node:    s.nodes[id],
+			closure: x,
+		}
+	}
+	return &nodeClosureSet{
+		closures: d,
+	}
+}
+
+/* package transitive closures */
+
+type (
+	PkgClosureSet interface {
+		Closures() []PkgClosure
+		Get(Pkg) (PkgClosure, bool)
+	}
+	// PkgClosure is the sizes of the transitive closures of a package, excluding itself.
+	PkgClosure interface {
+		Pkg() Pkg
+		// Dependencies is the number of the packages that the package depends on transitively.
+		Dependencies() int
+		// Dependents is the number of the packages that depend on the package transitively.
+		Dependents() int
+	}
+	PkgClosureCalculator interface {
+		Add(ref, def Pkg)
+		Result() PkgClosureSet
+	}
+)
+
+type pkgClosure struct {
+	pkg     Pkg
+	closure *graph.Closure
+}
+
+func (s *pkgClosure) Pkg() Pkg          { return s.pkg }
+func (s *pkgClosure) Dependencies() int { return s.closure.Dependencies }
+func (s *pkgClosure) Dependents() int   { return s.closure.Dependents }
+func (s *pkgClosure) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"pkg":          s.pkg.ID(),
+		"dependencies": s.closure.Dependencies,
+		"dependents":   s.closure.Dependents,
+	})
+}
+
+type pkgClosureSet struct {
+	closures map[string]*pkgClosure
+}
+
+func (s *pkgClosureSet) MarshalJSON() ([]byte, error) { return json.Marshal(s.closures) }
+func (s *pkgClosureSet) Closures() []PkgClosure {
+	var (
+		i        int
+		closures = make([]PkgClosure, len(s.closures))
+	)
+	for _, x := range s.closures {
+		closures[i] = x
+		i++
+	}
+	return closures
+}
+func (s *pkgClosureSet) Get(pkg Pkg) (PkgClosure, bool) {
+	x, ok := s.closures[pkg.ID()]
+	return x, ok
+}
+
+func NewPkgClosureCalculator() PkgClosureCalculator {
+	return &pkgClosureCalculator{
+		pkgs:  map[string]Pkg{},
+		graph: graph.New(),
+	}
+}
+
+type pkgClosureCalculator struct {
+	pkgs  map[string]Pkg
+	graph graph.Graph
+}
+
+func (s *pkgClosureCalculator) Add(ref, def Pkg) {
+	s.pkgs[ref.ID()] = ref
+	s.pkgs[def.ID()] = def
+	s.graph.AddEdge(ref.ID(), def.ID(), 1)
+}
+
+func (s *pkgClosureCalculator) Result() PkgClosureSet {
+	d := map[string]*pkgClosure{}
+	for id, x := range graph.Closures(s.graph) {
+		d[id] = &pkgClosure{
+			pkg:     s.pkgs[id],
+			closure: x,
+		}
+	}
+	return &pkgClosureSet{
+		closures: d,
+	}
+}
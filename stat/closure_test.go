@@ -0,0 +1,68 @@
+package stat_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeClosureCalculator(t *testing.T) {
+	c := stat.NewNodeClosureCalculator()
+	for _, x := range []struct {
+		r string
+		d string
+	}{
+		{r: "p1", d: "p1"}, // self loop
+		{r: "p1", d: "p2"},
+		{r: "p2", d: "p3"},
+		{r: "p3", d: "p2"},
+		{r: "p3", d: "p4"},
+	} {
+		c.Add(&mockNode{
+			id: x.r,
+		}, &mockNode{
+			id: x.d,
+		})
+	}
+	got := map[string][]int{}
+	for _, x := range c.Result().Closures() {
+		got[x.Node().ID()] = []int{x.Dependencies(), x.Dependents()}
+	}
+	assert.Equal(t, map[string][]int{
+		"p1": {3, 0},
+		"p2": {2, 2},
+		"p3": {2, 2},
+		"p4": {0, 3},
+	}, got)
+}
+
+func TestPkgClosureCalculator(t *testing.T) {
+	const wantJSON = `{"p1":{"dependencies":2,"dependents":0,"pkg":"p1"},"p2":{"dependencies":1,"dependents":1,"pkg":"p2"},"p3":{"dependencies":0,"dependents":2,"pkg":"p3"}}`
+	c := stat.NewPkgClosureCalculator()
+	for _, x := range []struct {
+		r string
+		d string
+	}{
+		{r: "p1", d: "p2"},
+		{r: "p2", d: "p3"},
+		{r: "p2", d: "p3"},
+	} {
+		c.Add(&mockPkg{
+			id: x.r,
+		}, &mockPkg{
+			id: x.d,
+		})
+	}
+
+	var (
+		want interface{}
+		got  interface{}
+	)
+	gotJSON, err := json.Marshal(c.Result())
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal([]byte(wantJSON), &want))
+	assert.Nil(t, json.Unmarshal(gotJSON, &got))
+	assert.Equal(t, want, got)
+}
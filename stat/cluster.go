@@ -0,0 +1,117 @@
+package stat
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/berquerant/gotypegraph/graph"
+)
+
+type (
+	// NodeCluster is a group of the nodes in a package that depend on each other densely.
+	NodeCluster interface {
+		Pkg() Pkg
+		Nodes() []Node
+		// Weight is the sum of the weights of the dependencies in the cluster.
+		Weight() int
+		// CutWeight is the sum of the weights of the dependencies between the cluster
+		// and the other clusters in the same package.
+		CutWeight() int
+		// Cohesion is the ratio of Weight to the weights of all the dependencies of the cluster in the package.
+		Cohesion() float64
+	}
+
+	nodeCluster struct {
+		pkg       Pkg
+		nodes     []Node
+		weight    int
+		cutWeight int
+	}
+)
+
+func (s *nodeCluster) Pkg() Pkg       { return s.pkg }
+func (s *nodeCluster) Nodes() []Node  { return s.nodes }
+func (s *nodeCluster) Weight() int    { return s.weight }
+func (s *nodeCluster) CutWeight() int { return s.cutWeight }
+func (s *nodeCluster) Cohesion() float64 {
+	if w := s.weight + s.cutWeight; w > 0 {
+		return float64(s.weight) / float64(w)
+	}
+	return 0
+}
+func (s *nodeCluster) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"pkg":        s.pkg,
+		"nodes":      s.nodes,
+		"weight":     s.weight,
+		"cut_weight": s.cutWeight,
+		"cohesion":   s.Cohesion(),
+	})
+}
+
+// NewNodeClusters detects the clusters of the nodes in each package by graph.Communities.
+// Only the dependencies in the same package are considered, self dependencies are ignored.
+// The clusters are sorted by package and the first node.
+func NewNodeClusters(deps []NodeDep) []NodeCluster {
+	var (
+		pkgs   = map[string]Pkg{}
+		nodes  = map[string]Node{}
+		graphs = map[string]graph.Graph{} // pkg => graph of the nodes in the pkg
+	)
+	addNode := func(node Node) {
+		id := node.Pkg().ID()
+		if _, found := graphs[id]; !found {
+			pkgs[id] = node.Pkg()
+			graphs[id] = graph.New()
+		}
+		nodes[node.ID()] = node
+		graphs[id].AddNode(node.ID())
+	}
+	for _, dep := range deps {
+		addNode(dep.Ref())
+		addNode(dep.Def())
+		if dep.Ref().Pkg().ID() == dep.Def().Pkg().ID() && dep.Ref().ID() != dep.Def().ID() {
+			graphs[dep.Ref().Pkg().ID()].AddEdge(dep.Ref().ID(), dep.Def().ID(), dep.Weight())
+		}
+	}
+
+	pkgIDs := make([]string, 0, len(graphs))
+	for id := range graphs {
+		pkgIDs = append(pkgIDs, id)
+	}
+	sort.Strings(pkgIDs)
+
+	clusters := []NodeCluster{}
+	for _, pkgID := range pkgIDs {
+		var (
+			g           = graphs[pkgID]
+			communities = graph.Communities(g)
+			clusterOf   = map[string]int{}
+			pkgClusters = make([]*nodeCluster, len(communities))
+		)
+		for i, c := range communities {
+			cluster := &nodeCluster{
+				pkg:   pkgs[pkgID],
+				nodes: make([]Node, len(c)),
+			}
+			for j, x := range c {
+				cluster.nodes[j] = nodes[x]
+				clusterOf[x] = i
+			}
+			pkgClusters[i] = cluster
+		}
+		for _, e := range g.Edges() {
+			from, to := clusterOf[e.From()], clusterOf[e.To()]
+			if from == to {
+				pkgClusters[from].weight += e.Weight()
+				continue
+			}
+			pkgClusters[from].cutWeight += e.Weight()
+			pkgClusters[to].cutWeight += e.Weight()
+		}
+		for _, x := range pkgClusters {
+			clusters = append(clusters, x)
+		}
+	}
+	return clusters
+}
@@ -0,0 +1,65 @@
+package stat_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPkgNode struct {
+	id  string
+	pkg string
+}
+
+func (s *mockPkgNode) ID() string      { return s.pkg + "." + s.id }
+func (s *mockPkgNode) Pkg() stat.Pkg   { return &mockPkg{id: s.pkg} }
+func (*mockPkgNode) Node() search.Node { return nil }
+
+func TestNewNodeClusters(t *testing.T) {
+	c := stat.NewNodeDepCalculator()
+	for _, x := range []struct {
+		r, d string
+		n    int
+	}{
+		{r: "a", d: "b", n: 3},
+		{r: "b", d: "c", n: 2},
+		{r: "c", d: "a", n: 2},
+		{r: "d", d: "e", n: 3},
+		{r: "e", d: "f", n: 2},
+		{r: "f", d: "d", n: 2},
+		{r: "c", d: "d", n: 1},
+		{r: "a", d: "a", n: 1}, // self loop
+	} {
+		for i := 0; i < x.n; i++ {
+			c.Add(&mockPkgNode{id: x.r, pkg: "p"}, &mockPkgNode{id: x.d, pkg: "p"})
+		}
+	}
+	c.Add(&mockPkgNode{id: "a", pkg: "p"}, &mockPkgNode{id: "x", pkg: "q"}) // other package
+
+	type cluster struct {
+		pkg       string
+		nodes     []string
+		weight    int
+		cutWeight int
+	}
+	got := []cluster{}
+	for _, x := range stat.NewNodeClusters(c.Result()) {
+		nodes := make([]string, len(x.Nodes()))
+		for i, n := range x.Nodes() {
+			nodes[i] = n.ID()
+		}
+		got = append(got, cluster{
+			pkg:       x.Pkg().ID(),
+			nodes:     nodes,
+			weight:    x.Weight(),
+			cutWeight: x.CutWeight(),
+		})
+	}
+	assert.Equal(t, []cluster{
+		{pkg: "p", nodes: []string{"p.a", "p.b", "p.c"}, weight: 7, cutWeight: 1},
+		{pkg: "p", nodes: []string{"p.d", "p.e", "p.f"}, weight: 7, cutWeight: 1},
+		{pkg: "q", nodes: []string{"q.x"}},
+	}, got)
+}
@@ -0,0 +1,151 @@
+package stat
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/berquerant/gotypegraph/graph"
+	"github.com/berquerant/gotypegraph/search"
+)
+
+type (
+	// TypeCohesion is the LCOM4 of a named type,
+	// the number of the connected components of the methods
+	// that are connected when a method uses a field or calls another method of the type.
+	TypeCohesion interface {
+		Pkg() Pkg
+		// Name is the name of the type.
+		Name() string
+		// Components are the methods and the fields used by them.
+		Components() [][]Node
+		LCOM() int
+	}
+
+	typeCohesion struct {
+		pkg        Pkg
+		name       string
+		components [][]Node
+	}
+
+	TypeCohesionCalculator interface {
+		// AddMethod adds the method that may not use anything.
+		AddMethod(method Node)
+		Add(ref, def Node)
+		// Result returns the cohesions sorted by LCOM desc, package and name.
+		Result() []TypeCohesion
+	}
+)
+
+func (s *typeCohesion) Pkg() Pkg             { return s.pkg }
+func (s *typeCohesion) Name() string         { return s.name }
+func (s *typeCohesion) Components() [][]Node { return s.components }
+func (s *typeCohesion) LCOM() int            { return len(s.components) }
+func (s *typeCohesion) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"pkg":        s.pkg,
+		"name":       s.name,
+		"components": s.components,
+		"lcom":       s.LCOM(),
+	})
+}
+
+func NewTypeCohesionCalculator() TypeCohesionCalculator {
+	return &typeCohesionCalculator{
+		types: map[string]*typeCohesionMembers{},
+	}
+}
+
+type (
+	typeCohesionCalculator struct {
+		types map[string]*typeCohesionMembers // pkg and type name => members
+	}
+
+	typeCohesionMembers struct {
+		pkg   Pkg
+		name  string
+		nodes map[string]Node
+		graph graph.Graph // uses between the members
+	}
+)
+
+func (*typeCohesionCalculator) recv(node Node) string {
+	return node.Node().RecvString(search.WithNodeRawRecv(true))
+}
+
+func (s *typeCohesionCalculator) members(node Node) *typeCohesionMembers {
+	var (
+		name = s.recv(node)
+		id   = node.Pkg().ID() + "." + name
+	)
+	if x, found := s.types[id]; found {
+		return x
+	}
+	x := &typeCohesionMembers{
+		pkg:   node.Pkg(),
+		name:  name,
+		nodes: map[string]Node{},
+		graph: graph.New(),
+	}
+	s.types[id] = x
+	return x
+}
+
+func (s *typeCohesionMembers) add(node Node) {
+	s.nodes[node.ID()] = node
+	s.graph.AddNode(node.ID())
+}
+
+func (s *typeCohesionCalculator) AddMethod(method Node) {
+	if method.Node().Type() != search.MethodNodeType {
+		return
+	}
+	s.members(method).add(method)
+}
+
+func (s *typeCohesionCalculator) Add(ref, def Node) {
+	if ref.Node().Type() != search.MethodNodeType {
+		return
+	}
+	members := s.members(ref)
+	members.add(ref)
+	switch def.Node().Type() {
+	case search.MethodNodeType, search.FieldNodeType:
+	default:
+		return
+	}
+	if ref.ID() == def.ID() || ref.Pkg().ID() != def.Pkg().ID() || s.recv(ref) != s.recv(def) {
+		return
+	}
+	members.add(def)
+	members.graph.AddEdge(ref.ID(), def.ID(), 1)
+}
+
+func (s *typeCohesionCalculator) Result() []TypeCohesion {
+	r := []TypeCohesion{}
+	for _, members := range s.types {
+		x := &typeCohesion{
+			pkg:        members.pkg,
+			name:       members.name,
+			components: [][]Node{},
+		}
+		for _, c := range graph.ConnectedComponents(members.graph) {
+			// a field is always connected to a method
+			nodes := make([]Node, len(c))
+			for i, id := range c {
+				nodes[i] = members.nodes[id]
+			}
+			x.components = append(x.components, nodes)
+		}
+		r = append(r, x)
+	}
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].LCOM() != r[j].LCOM() {
+			return r[i].LCOM() > r[j].LCOM()
+		}
+		if r[i].Pkg().ID() != r[j].Pkg().ID() {
+			return r[i].Pkg().ID() < r[j].Pkg().ID()
+		}
+		return r[i].Name() < r[j].Name()
+	})
+	return r
+}
@@ -0,0 +1,93 @@
+package stat_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeCohesionCalculator(t *testing.T) {
+	const src = `package testpkg
+type T struct{ a, b, c int }
+func (t *T) A() int { return t.a }
+func (t *T) B() int { return t.a + t.A() }
+func (t *T) C() int { return t.b }
+func (T) D() {}
+type U int
+func (U) E() {}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if !assert.Nil(t, err) {
+		return
+	}
+	var conf types.Config
+	p, err := conf.Check("testpkg", fset, []*ast.File{f}, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	pkg := search.NewPkgWithName("testpkg", "testpkg")
+	method := func(typeName, name string) stat.Node {
+		obj, _, _ := types.LookupFieldOrMethod(p.Scope().Lookup(typeName).Type(), true, p, name)
+		return stat.NewNode(search.NewDefNode(pkg, obj, &search.NodeInfo{}))
+	}
+	field := func(typeName, name string) stat.Node {
+		obj, _, _ := types.LookupFieldOrMethod(p.Scope().Lookup(typeName).Type(), true, p, name)
+		return stat.NewNode(search.NewDefNode(pkg, obj, &search.NodeInfo{Recv: typeName}))
+	}
+
+	c := stat.NewTypeCohesionCalculator()
+	for _, x := range []stat.Node{
+		method("T", "A"),
+		method("T", "B"),
+		method("T", "C"),
+		method("T", "D"),
+		method("U", "E"),
+	} {
+		c.AddMethod(x)
+	}
+	c.Add(method("T", "A"), field("T", "a"))
+	c.Add(method("T", "B"), field("T", "a"))
+	c.Add(method("T", "B"), method("T", "A"))
+	c.Add(method("T", "C"), field("T", "b"))
+
+	type cohesion struct {
+		name       string
+		components [][]string
+	}
+	got := []cohesion{}
+	for _, x := range c.Result() {
+		components := make([][]string, len(x.Components()))
+		for i, c := range x.Components() {
+			for _, node := range c {
+				components[i] = append(components[i], node.Node().Name())
+			}
+		}
+		got = append(got, cohesion{
+			name:       x.Name(),
+			components: components,
+		})
+	}
+	assert.Equal(t, []cohesion{
+		{
+			name: "T",
+			components: [][]string{
+				{"A", "B", "a"},
+				{"C", "b"},
+				{"D"},
+			},
+		},
+		{
+			name: "U",
+			components: [][]string{
+				{"E"},
+			},
+		},
+	}, got)
+}
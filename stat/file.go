@@ -0,0 +1,232 @@
+package stat
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"sort"
+
+	"github.com/berquerant/gotypegraph/search"
+)
+
+type (
+	// File is a source file of a package.
+	File interface {
+		// ID returns the filename, or the package id if the position is unknown, e.g. builtin.
+		ID() string
+		// Name returns the base name of the file.
+		Name() string
+		Pkg() Pkg
+	}
+
+	file struct {
+		filename string
+		pkg      Pkg
+	}
+)
+
+func NewFile(filename string, pkg Pkg) File {
+	return &file{
+		filename: filename,
+		pkg:      pkg,
+	}
+}
+
+// NewRefFile returns the file that contains the ident of the reference.
+func NewRefFile(ref search.RefNode) File {
+	return newFileFromPos(ref.Pkg(), ref.Ident().Pos(), nil)
+}
+
+// NewDefFile returns the file that contains the definition.
+// fset is used if the package of the definition is not loaded.
+func NewDefFile(def search.DefNode, fset *token.FileSet) File {
+	return newFileFromPos(def.Pkg(), def.Obj().Pos(), fset)
+}
+
+func newFileFromPos(pkg search.Pkg, pos token.Pos, fset *token.FileSet) File {
+	if p := pkg.Pkg(); p != nil && p.Fset != nil {
+		fset = p.Fset
+	}
+	var filename string
+	if fset != nil && pos.IsValid() {
+		filename = fset.Position(pos).Filename
+	}
+	return NewFile(filename, NewPkg(pkg))
+}
+
+func (s *file) ID() string {
+	if s.filename == "" {
+		return s.pkg.ID()
+	}
+	return s.filename
+}
+func (s *file) Name() string { return filepath.Base(s.ID()) }
+func (s *file) Pkg() Pkg     { return s.pkg }
+
+/* file stat of dependencies */
+
+type (
+	FileStat interface {
+		File() File
+		// Ref returns the number of the references from the file.
+		Ref() int
+		// Def returns the number of the references to the file.
+		Def() int
+		// UniqRef returns the number of the files referenced by the file.
+		UniqRef() int
+		// UniqDef returns the number of the files that reference the file.
+		UniqDef() int
+		Weight() int
+	}
+	FileStatCalculator interface {
+		Add(ref, def File)
+		// Result returns the stats sorted by file id.
+		Result() []FileStat
+	}
+)
+
+type fileStat struct {
+	file File
+	refs map[string]int
+	defs map[string]int
+}
+
+func newFileStat(file File) *fileStat {
+	return &fileStat{
+		file: file,
+		refs: map[string]int{},
+		defs: map[string]int{},
+	}
+}
+
+func (s *fileStat) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"file":   s.file.ID(),
+		"pkg":    s.file.Pkg().ID(),
+		"refs":   s.refs,
+		"defs":   s.defs,
+		"weight": s.Weight(),
+	})
+}
+func (s *fileStat) File() File   { return s.file }
+func (s *fileStat) Ref() int     { return sumValues(s.refs) }
+func (s *fileStat) Def() int     { return sumValues(s.defs) }
+func (s *fileStat) UniqRef() int { return len(s.refs) }
+func (s *fileStat) UniqDef() int { return len(s.defs) }
+func (s *fileStat) Weight() int  { return s.Ref() + s.Def() }
+
+func sumValues(d map[string]int) int {
+	var n int
+	for _, x := range d {
+		n += x
+	}
+	return n
+}
+
+func NewFileStatCalculator() FileStatCalculator {
+	return &fileStatCalculator{
+		d: map[string]*fileStat{},
+	}
+}
+
+type fileStatCalculator struct {
+	d map[string]*fileStat
+}
+
+func (s *fileStatCalculator) get(file File) *fileStat {
+	x, ok := s.d[file.ID()]
+	if !ok {
+		x = newFileStat(file)
+		s.d[file.ID()] = x
+	}
+	return x
+}
+
+func (s *fileStatCalculator) Add(ref, def File) {
+	s.get(ref).refs[def.ID()]++
+	s.get(def).defs[ref.ID()]++
+}
+
+func (s *fileStatCalculator) Result() []FileStat {
+	r := make([]FileStat, 0, len(s.d))
+	for _, x := range s.d {
+		r = append(r, x)
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].File().ID() < r[j].File().ID() })
+	return r
+}
+
+/* file dependencies */
+
+type (
+	FileDep interface {
+		Ref() File
+		Def() File
+		Weight() int
+	}
+
+	fileDep struct {
+		ref    File
+		def    File
+		weight int
+	}
+
+	FileDepCalculator interface {
+		Add(ref, def File)
+		// Result returns the dependencies sorted by ref and def.
+		Result() []FileDep
+	}
+)
+
+func (s *fileDep) Ref() File   { return s.ref }
+func (s *fileDep) Def() File   { return s.def }
+func (s *fileDep) Weight() int { return s.weight }
+func (s *fileDep) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"ref":    s.ref.ID(),
+		"def":    s.def.ID(),
+		"weight": s.weight,
+	})
+}
+
+func NewFileDepCalculator() FileDepCalculator {
+	return &fileDepCalculator{
+		d: map[string]*fileDep{},
+	}
+}
+
+type fileDepCalculator struct {
+	d map[string]*fileDep
+}
+
+func (*fileDepCalculator) id(ref, def File) string {
+	return fmt.Sprintf("%s>%s", ref.ID(), def.ID())
+}
+
+func (s *fileDepCalculator) Add(ref, def File) {
+	id := s.id(ref, def)
+	if dep, found := s.d[id]; found {
+		dep.weight++
+		return
+	}
+	s.d[id] = &fileDep{
+		ref:    ref,
+		def:    def,
+		weight: 1,
+	}
+}
+
+func (s *fileDepCalculator) Result() []FileDep {
+	r := make([]FileDep, 0, len(s.d))
+	for _, x := range s.d {
+		r = append(r, x)
+	}
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].Ref().ID() != r[j].Ref().ID() {
+			return r[i].Ref().ID() < r[j].Ref().ID()
+		}
+		return r[i].Def().ID() < r[j].Def().ID()
+	})
+	return r
+}
@@ -0,0 +1,73 @@
+package stat_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCalculator(t *testing.T) {
+	var (
+		p1 = &mockPkg{id: "p1"}
+		p2 = &mockPkg{id: "p2"}
+		a  = stat.NewFile("/src/p1/a.go", p1)
+		b  = stat.NewFile("/src/p1/b.go", p1)
+		c  = stat.NewFile("/src/p2/c.go", p2)
+		u  = stat.NewFile("", p2)
+
+		depCalc  = stat.NewFileDepCalculator()
+		statCalc = stat.NewFileStatCalculator()
+	)
+	for _, x := range [][2]stat.File{
+		{a, b},
+		{a, b},
+		{a, c},
+		{b, c},
+		{c, u},
+		{a, a},
+	} {
+		depCalc.Add(x[0], x[1])
+		statCalc.Add(x[0], x[1])
+	}
+
+	assert.Equal(t, "p2", u.ID())
+	assert.Equal(t, "a.go", a.Name())
+
+	type dep struct {
+		ref, def string
+		weight   int
+	}
+	var gotDeps []dep
+	for _, x := range depCalc.Result() {
+		gotDeps = append(gotDeps, dep{ref: x.Ref().ID(), def: x.Def().ID(), weight: x.Weight()})
+	}
+	assert.Equal(t, []dep{
+		{ref: "/src/p1/a.go", def: "/src/p1/a.go", weight: 1},
+		{ref: "/src/p1/a.go", def: "/src/p1/b.go", weight: 2},
+		{ref: "/src/p1/a.go", def: "/src/p2/c.go", weight: 1},
+		{ref: "/src/p1/b.go", def: "/src/p2/c.go", weight: 1},
+		{ref: "/src/p2/c.go", def: "p2", weight: 1},
+	}, gotDeps)
+
+	type st struct {
+		file                       string
+		ref, def, uniqRef, uniqDef int
+	}
+	var gotStats []st
+	for _, x := range statCalc.Result() {
+		gotStats = append(gotStats, st{
+			file:    x.File().ID(),
+			ref:     x.Ref(),
+			def:     x.Def(),
+			uniqRef: x.UniqRef(),
+			uniqDef: x.UniqDef(),
+		})
+	}
+	assert.Equal(t, []st{
+		{file: "/src/p1/a.go", ref: 4, def: 1, uniqRef: 3, uniqDef: 1},
+		{file: "/src/p1/b.go", ref: 1, def: 2, uniqRef: 1, uniqDef: 1},
+		{file: "/src/p2/c.go", ref: 1, def: 2, uniqRef: 1, uniqDef: 2},
+		{file: "p2", def: 1, uniqDef: 1},
+	}, gotStats)
+}
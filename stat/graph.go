@@ -0,0 +1,39 @@
+package stat
+
+import "github.com/berquerant/gotypegraph/graph"
+
+// NewNodeGraph converts the node dependencies into a graph whose node ids are Node.ID().
+func NewNodeGraph(deps []NodeDep) graph.Graph {
+	g := graph.New()
+	for _, x := range deps {
+		g.AddEdge(x.Ref().ID(), x.Def().ID(), x.Weight())
+	}
+	return g
+}
+
+// NewPkgGraph converts the package dependencies into a graph whose node ids are Pkg.ID().
+func NewPkgGraph(deps []PkgDep) graph.Graph {
+	g := graph.New()
+	for _, x := range deps {
+		g.AddEdge(x.Ref().ID(), x.Def().ID(), x.Weight())
+	}
+	return g
+}
+
+// NewGroupGraph converts the group dependencies into a graph whose node ids are Group.ID().
+func NewGroupGraph(deps []GroupDep) graph.Graph {
+	g := graph.New()
+	for _, x := range deps {
+		g.AddEdge(x.Ref().ID(), x.Def().ID(), x.Weight())
+	}
+	return g
+}
+
+// NewFileGraph converts the file dependencies into a graph whose node ids are File.ID().
+func NewFileGraph(deps []FileDep) graph.Graph {
+	g := graph.New()
+	for _, x := range deps {
+		g.AddEdge(x.Ref().ID(), x.Def().ID(), x.Weight())
+	}
+	return g
+}
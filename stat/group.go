@@ -0,0 +1,314 @@
+package stat
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+type (
+	// Group is a set of packages.
+	Group interface {
+		ID() string
+	}
+
+	group struct {
+		name string
+	}
+
+	// GroupFunc determines the group of the package.
+	GroupFunc func(Pkg) Group
+)
+
+func NewGroup(name string) Group {
+	return &group{
+		name: name,
+	}
+}
+
+func (s *group) ID() string { return s.name }
+
+/* group stat of dependencies */
+
+type (
+	GroupStatSet interface {
+		Stats() []GroupStat
+		Get(Group) (GroupStat, bool)
+	}
+	GroupStat interface {
+		Group() Group
+		// Pkgs returns the packages in the group, sorted by id.
+		Pkgs() []Pkg
+		Weight() int
+		Refs() GroupStatCell
+		Defs() GroupStatCell
+	}
+	GroupStatCell interface {
+		Group() Group
+		Deps() []GroupStatDep
+		Get(Group) (GroupStatDep, bool)
+		Weight() int
+	}
+	GroupStatDep interface {
+		Group() Group
+		Weight() int
+	}
+	GroupStatCalculator interface {
+		Add(ref, def Pkg)
+		Result() GroupStatSet
+	}
+)
+
+type groupStatSet struct {
+	stats map[string]*groupStat
+}
+
+func (s *groupStatSet) MarshalJSON() ([]byte, error) { return json.Marshal(s.stats) }
+
+func (s *groupStatSet) Stats() []GroupStat {
+	var (
+		i     int
+		stats = make([]GroupStat, len(s.stats))
+	)
+	for _, x := range s.stats {
+		stats[i] = x
+		i++
+	}
+	return stats
+}
+
+func (s *groupStatSet) Get(group Group) (GroupStat, bool) {
+	x, ok := s.stats[group.ID()]
+	return x, ok
+}
+
+func newGroupStat(group Group) *groupStat {
+	return &groupStat{
+		group: group,
+		pkgs:  map[string]Pkg{},
+		defs:  newGroupStatCell(group),
+		refs:  newGroupStatCell(group),
+	}
+}
+
+type groupStat struct {
+	group Group
+	pkgs  map[string]Pkg
+	defs  *groupStatCell
+	refs  *groupStatCell
+}
+
+func (s *groupStat) MarshalJSON() ([]byte, error) {
+	pkgs := make([]string, len(s.pkgs))
+	for i, x := range s.Pkgs() {
+		pkgs[i] = x.ID()
+	}
+	d := map[string]interface{}{
+		"group":  s.group.ID(),
+		"pkgs":   pkgs,
+		"defs":   s.defs,
+		"refs":   s.refs,
+		"weight": s.Weight(),
+	}
+	if m, ok := s.group.(ModuleGroup); ok && m.Version() != "" {
+		d["version"] = m.Version()
+	}
+	return json.Marshal(d)
+}
+func (s *groupStat) Group() Group        { return s.group }
+func (s *groupStat) Defs() GroupStatCell { return s.defs }
+func (s *groupStat) Refs() GroupStatCell { return s.refs }
+func (s *groupStat) Pkgs() []Pkg {
+	pkgs := make([]Pkg, 0, len(s.pkgs))
+	for _, x := range s.pkgs {
+		pkgs = append(pkgs, x)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].ID() < pkgs[j].ID() })
+	return pkgs
+}
+func (s *groupStat) Weight() int { return s.defs.Weight() + s.refs.Weight() }
+
+func newGroupStatDep(group Group) *groupStatDep {
+	return &groupStatDep{
+		group:  group,
+		weight: 1,
+	}
+}
+
+type groupStatDep struct {
+	group  Group
+	weight int
+}
+
+func (s *groupStatDep) Group() Group { return s.group }
+func (s *groupStatDep) Weight() int  { return s.weight }
+func (s *groupStatDep) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"group":  s.group.ID(),
+		"weight": s.weight,
+	})
+}
+
+func newGroupStatCell(group Group) *groupStatCell {
+	return &groupStatCell{
+		group: group,
+		deps:  map[string]*groupStatDep{},
+	}
+}
+
+type groupStatCell struct {
+	group Group
+	deps  map[string]*groupStatDep
+}
+
+func (s *groupStatCell) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"group": s.group.ID(),
+		"deps":  s.deps,
+	})
+}
+func (s *groupStatCell) add(group Group) {
+	if x, ok := s.deps[group.ID()]; ok {
+		x.weight++
+		return
+	}
+	s.deps[group.ID()] = newGroupStatDep(group)
+}
+func (s *groupStatCell) Group() Group { return s.group }
+func (s *groupStatCell) Deps() []GroupStatDep {
+	var (
+		deps = make([]GroupStatDep, len(s.deps))
+		i    int
+	)
+	for _, dep := range s.deps {
+		deps[i] = dep
+		i++
+	}
+	return deps
+}
+func (s *groupStatCell) Get(group Group) (GroupStatDep, bool) {
+	dep, ok := s.deps[group.ID()]
+	return dep, ok
+}
+func (s *groupStatCell) Weight() int {
+	var n int
+	for _, dep := range s.deps {
+		n += dep.weight
+	}
+	return n
+}
+
+func NewGroupStatCalculator(groupOf GroupFunc) GroupStatCalculator {
+	return &groupStatCalculator{
+		groupOf: groupOf,
+		d:       map[string]*groupStat{},
+	}
+}
+
+type groupStatCalculator struct {
+	groupOf GroupFunc
+	d       map[string]*groupStat
+}
+
+func (s *groupStatCalculator) get(pkg Pkg) *groupStat {
+	group := s.groupOf(pkg)
+	x, ok := s.d[group.ID()]
+	if !ok {
+		x = newGroupStat(group)
+		s.d[group.ID()] = x
+	}
+	x.pkgs[pkg.ID()] = pkg
+	return x
+}
+
+func (s *groupStatCalculator) Add(ref, def Pkg) {
+	var (
+		r = s.get(ref)
+		d = s.get(def)
+	)
+	r.refs.add(d.group)
+	d.defs.add(r.group)
+}
+
+func (s *groupStatCalculator) Result() GroupStatSet {
+	return &groupStatSet{
+		stats: s.d,
+	}
+}
+
+/* group dependencies */
+
+type (
+	GroupDep interface {
+		Ref() Group
+		Def() Group
+		Weight() int
+	}
+
+	groupDep struct {
+		ref    Group
+		def    Group
+		weight int
+	}
+
+	GroupDepCalculator interface {
+		Add(ref, def Pkg)
+		Result() []GroupDep
+	}
+)
+
+func (s *groupDep) Ref() Group  { return s.ref }
+func (s *groupDep) Def() Group  { return s.def }
+func (s *groupDep) Weight() int { return s.weight }
+func (s *groupDep) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"ref":    s.ref.ID(),
+		"def":    s.def.ID(),
+		"weight": s.weight,
+	})
+}
+
+func NewGroupDepCalculator(groupOf GroupFunc) GroupDepCalculator {
+	return &groupDepCalculator{
+		groupOf: groupOf,
+		d:       map[string]*groupDep{},
+	}
+}
+
+type groupDepCalculator struct {
+	groupOf GroupFunc
+	d       map[string]*groupDep
+}
+
+func (*groupDepCalculator) id(ref, def Group) string {
+	return fmt.Sprintf("%s>%s", ref.ID(), def.ID())
+}
+
+func (s *groupDepCalculator) Add(ref, def Pkg) {
+	var (
+		r  = s.groupOf(ref)
+		d  = s.groupOf(def)
+		id = s.id(r, d)
+	)
+	if dep, found := s.d[id]; found {
+		dep.weight++
+		return
+	}
+	s.d[id] = &groupDep{
+		ref:    r,
+		def:    d,
+		weight: 1,
+	}
+}
+
+func (s *groupDepCalculator) Result() []GroupDep {
+	var (
+		i int
+		r = make([]GroupDep, len(s.d))
+	)
+	for _, x := range s.d {
+		r[i] = x
+		i++
+	}
+	return r
+}
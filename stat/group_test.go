@@ -0,0 +1,80 @@
+package stat_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+// groupByPrefix groups a/x and a/y into a.
+func groupByPrefix(pkg stat.Pkg) stat.Group {
+	return stat.NewGroup(strings.SplitN(pkg.ID(), "/", 2)[0])
+}
+
+var groupTestDeps = []struct {
+	r string
+	d string
+}{
+	{r: "a/x", d: "a/y"},
+	{r: "a/x", d: "b/x"},
+	{r: "a/y", d: "b/x"},
+	{r: "b/x", d: "c"},
+}
+
+func TestGroupStatCalculator(t *testing.T) {
+	const wantJSON = `{"a":{"defs":{"deps":{"a":{"group":"a","weight":1}},"group":"a"},"group":"a","pkgs":["a/x","a/y"],"refs":{"deps":{"a":{"group":"a","weight":1},"b":{"group":"b","weight":2}},"group":"a"},"weight":4},"b":{"defs":{"deps":{"a":{"group":"a","weight":2}},"group":"b"},"group":"b","pkgs":["b/x"],"refs":{"deps":{"c":{"group":"c","weight":1}},"group":"b"},"weight":3},"c":{"defs":{"deps":{"b":{"group":"b","weight":1}},"group":"c"},"group":"c","pkgs":["c"],"refs":{"deps":{},"group":"c"},"weight":1}}`
+
+	c := stat.NewGroupStatCalculator(groupByPrefix)
+	for _, x := range groupTestDeps {
+		c.Add(&mockPkg{
+			id: x.r,
+		}, &mockPkg{
+			id: x.d,
+		})
+	}
+
+	var (
+		want interface{}
+		got  interface{}
+	)
+	gotJSON, err := json.Marshal(c.Result())
+	assert.Nil(t, err)
+	t.Logf("%s", gotJSON)
+	assert.Nil(t, json.Unmarshal([]byte(wantJSON), &want))
+	assert.Nil(t, json.Unmarshal(gotJSON, &got))
+	assert.Equal(t, want, got)
+}
+
+func TestGroupDepCalculator(t *testing.T) {
+	want := map[string]map[string]int{
+		"a": {
+			"a": 1,
+			"b": 2,
+		},
+		"b": {
+			"c": 1,
+		},
+	}
+	wantLen := 3
+
+	c := stat.NewGroupDepCalculator(groupByPrefix)
+	for _, x := range groupTestDeps {
+		c.Add(&mockPkg{
+			id: x.r,
+		}, &mockPkg{
+			id: x.d,
+		})
+	}
+	got := c.Result()
+	assert.Equal(t, wantLen, len(got))
+	for _, g := range got {
+		dst, ok := want[g.Ref().ID()]
+		assert.True(t, ok)
+		w, ok := dst[g.Def().ID()]
+		assert.True(t, ok)
+		assert.Equal(t, w, g.Weight())
+	}
+}
@@ -0,0 +1,83 @@
+package stat
+
+import (
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type (
+	// ModuleGroup is a group of the packages in a module.
+	ModuleGroup interface {
+		Group
+		// Version returns the version of the module, empty for the main module and the standard library.
+		Version() string
+	}
+
+	moduleGroup struct {
+		path    string
+		version string
+	}
+)
+
+func NewModuleGroup(path, version string) ModuleGroup {
+	return &moduleGroup{
+		path:    path,
+		version: version,
+	}
+}
+
+func (s *moduleGroup) ID() string      { return s.path }
+func (s *moduleGroup) Version() string { return s.version }
+
+// StdModule is the module path of the standard library and the builtin package.
+const StdModule = "std"
+
+// NewModuleGroupFunc returns a GroupFunc that groups the packages by their modules.
+// The modules of the packages that are not loaded, e.g. foreign packages, are found in the imports of pkgs.
+// The packages in no modules belong to StdModule if the first element of the path has no dots, otherwise to themselves.
+func NewModuleGroupFunc(pkgs []*packages.Package) GroupFunc {
+	imported := map[string]*packages.Module{} // pkg path => module
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		if p.Module != nil {
+			imported[p.PkgPath] = p.Module
+		}
+	})
+
+	return func(pkg Pkg) Group {
+		id := pkg.ID()
+		m := imported[id]
+		if p := pkg.Pkg().Pkg(); p != nil && p.Module != nil {
+			m = p.Module
+		}
+		if m != nil {
+			return newModuleGroupFromModule(m)
+		}
+		if !strings.Contains(strings.SplitN(id, "/", 2)[0], ".") {
+			return NewModuleGroup(StdModule, "")
+		}
+		return NewModuleGroup(id, "")
+	}
+}
+
+func newModuleGroupFromModule(m *packages.Module) ModuleGroup {
+	version := m.Version
+	if r := m.Replace; r != nil {
+		version = "=> " + r.Path
+		if r.Version != "" {
+			version += "@" + r.Version
+		}
+	}
+	return NewModuleGroup(m.Path, version)
+}
+
+// NewDirGroupFunc returns a GroupFunc that groups the packages by the first depth elements of their paths.
+func NewDirGroupFunc(depth int) GroupFunc {
+	return func(pkg Pkg) Group {
+		elems := strings.Split(pkg.ID(), "/")
+		if depth > 0 && len(elems) > depth {
+			elems = elems[:depth]
+		}
+		return NewGroup(strings.Join(elems, "/"))
+	}
+}
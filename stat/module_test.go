@@ -0,0 +1,127 @@
+package stat_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestModuleGroupFunc(t *testing.T) {
+	var (
+		mainModule = &packages.Module{
+			Path: "example.com/app",
+			Main: true,
+		}
+		libModule = &packages.Module{
+			Path:    "example.com/lib",
+			Version: "v1.2.0",
+		}
+		replacedModule = &packages.Module{
+			Path:    "example.com/fork",
+			Version: "v0.1.0",
+			Replace: &packages.Module{
+				Path:    "example.com/myfork",
+				Version: "v0.1.1",
+			},
+		}
+		nestedModule = &packages.Module{
+			Path:    "example.com/lib/v2",
+			Version: "v2.0.0",
+		}
+		// the foreign packages are not loaded but imported
+		fork = &packages.Package{
+			PkgPath: "example.com/fork",
+			Module:  replacedModule,
+		}
+		nested = &packages.Package{
+			PkgPath: "example.com/lib/v2/y",
+			Module:  nestedModule,
+		}
+		app = &packages.Package{
+			PkgPath: "example.com/app/sub",
+			Module:  mainModule,
+			Imports: map[string]*packages.Package{
+				fork.PkgPath:   fork,
+				nested.PkgPath: nested,
+			},
+		}
+		lib = &packages.Package{
+			PkgPath: "example.com/lib/x",
+			Module:  libModule,
+		}
+		groupOf = stat.NewModuleGroupFunc([]*packages.Package{app, lib})
+	)
+
+	for _, tc := range []struct {
+		title   string
+		pkg     search.Pkg
+		want    string
+		version string
+	}{
+		{
+			title: "main",
+			pkg:   search.NewPkg(app),
+			want:  "example.com/app",
+		},
+		{
+			title:   "dependency",
+			pkg:     search.NewPkg(lib),
+			want:    "example.com/lib",
+			version: "v1.2.0",
+		},
+		{
+			title:   "replaced",
+			pkg:     search.NewPkgWithName("fork", "example.com/fork"),
+			want:    "example.com/fork",
+			version: "=> example.com/myfork@v0.1.1",
+		},
+		{
+			title:   "nested module",
+			pkg:     search.NewPkgWithName("y", "example.com/lib/v2/y"),
+			want:    "example.com/lib/v2",
+			version: "v2.0.0",
+		},
+		{
+			title: "not imported",
+			pkg:   search.NewPkgWithName("z", "example.com/other/z"),
+			want:  "example.com/other/z",
+		},
+		{
+			title: "std",
+			pkg:   search.NewPkgWithName("http", "net/http"),
+			want:  stat.StdModule,
+		},
+		{
+			title: "builtin",
+			pkg:   search.NewBuiltinPkg(),
+			want:  stat.StdModule,
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			got, ok := groupOf(stat.NewPkg(tc.pkg)).(stat.ModuleGroup)
+			if !assert.True(t, ok) {
+				return
+			}
+			assert.Equal(t, tc.want, got.ID())
+			assert.Equal(t, tc.version, got.Version())
+		})
+	}
+}
+
+func TestDirGroupFunc(t *testing.T) {
+	groupOf := stat.NewDirGroupFunc(2)
+	for _, tc := range []struct {
+		pkg  string
+		want string
+	}{
+		{pkg: "a/b/c", want: "a/b"},
+		{pkg: "a/b", want: "a/b"},
+		{pkg: "a", want: "a"},
+	} {
+		assert.Equal(t, tc.want, groupOf(&mockPkg{id: tc.pkg}).ID(), tc.pkg)
+	}
+}
@@ -0,0 +1,51 @@
+package stat
+
+import (
+	"go/types"
+
+	"github.com/berquerant/gotypegraph/search"
+)
+
+// NewTypeNode returns the node of the named type that the method or the field belongs to, or the node itself.
+// The id of the returned node is the same as the id of the node of the type.
+func NewTypeNode(n search.Node) Node {
+	if obj := ownerTypeName(n); obj != nil {
+		return NewNode(search.NewDefNode(n.Pkg(), obj, &search.NodeInfo{
+			ValueSpecIndex: -1,
+		}))
+	}
+	return NewNode(n)
+}
+
+// ownerTypeName returns the named type that has the method or the field.
+func ownerTypeName(n search.Node) *types.TypeName {
+	switch n.Type() {
+	case search.MethodNodeType:
+		sig, ok := n.Obj().Type().(*types.Signature)
+		if !ok || sig.Recv() == nil {
+			return nil
+		}
+		t := sig.Recv().Type()
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		if nmd, ok := t.(*types.Named); ok {
+			return nmd.Obj()
+		}
+		return nil
+	case search.FieldNodeType:
+		var (
+			recv = n.RecvString(search.WithNodeRawRecv(true))
+			pkg  = n.Obj().Pkg()
+		)
+		if recv == "" || pkg == nil {
+			return nil
+		}
+		if obj, ok := pkg.Scope().Lookup(recv).(*types.TypeName); ok {
+			return obj
+		}
+		return nil
+	default:
+		return nil
+	}
+}
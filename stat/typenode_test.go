@@ -0,0 +1,68 @@
+package stat_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/berquerant/gotypegraph/search"
+	"github.com/berquerant/gotypegraph/stat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTypeNode(t *testing.T) {
+	const src = `package p
+type T struct {
+  a int
+}
+func (t *T) M() int { return t.a }
+type I interface {
+  N()
+}
+func F() {}`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if !assert.Nil(t, err) {
+		return
+	}
+	typesPkg, err := new(types.Config).Check("p", fset, []*ast.File{f}, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	var (
+		pkg           = search.NewPkgWithName("p", "p")
+		scope         = typesPkg.Scope()
+		typT          = scope.Lookup("T")
+		typI          = scope.Lookup("I")
+		fieldA, _, _  = types.LookupFieldOrMethod(typT.Type(), true, typesPkg, "a")
+		methodM, _, _ = types.LookupFieldOrMethod(typT.Type(), true, typesPkg, "M")
+		methodN, _, _ = types.LookupFieldOrMethod(typI.Type(), true, typesPkg, "N")
+		newNode       = func(obj types.Object, recv string) search.Node {
+			return search.NewDefNode(pkg, obj, &search.NodeInfo{
+				ValueSpecIndex: -1,
+				Recv:           recv,
+			})
+		}
+		idT = stat.NewNode(newNode(typT, "")).ID()
+		idI = stat.NewNode(newNode(typI, "")).ID()
+	)
+
+	for _, tc := range []struct {
+		title string
+		node  search.Node
+		want  string
+	}{
+		{title: "type", node: newNode(typT, ""), want: idT},
+		{title: "field", node: newNode(fieldA, "T"), want: idT},
+		{title: "method", node: newNode(methodM, ""), want: idT},
+		{title: "interface method", node: newNode(methodN, ""), want: idI},
+		{title: "func", node: newNode(scope.Lookup("F"), ""), want: stat.NewNode(newNode(scope.Lookup("F"), "")).ID()},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			assert.Equal(t, tc.want, stat.NewTypeNode(tc.node).ID())
+		})
+	}
+}
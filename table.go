@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/berquerant/gotypegraph/display"
+	"github.com/berquerant/gotypegraph/logger"
+)
+
+var tableDir = flag.String("table.dir", ".", "Directory to write edges and nodes tables when type is csv or tsv.")
+
+// newTableWriter returns a writer that writes edges.csv and nodes.csv (or .tsv) into the table directory.
+func newTableWriter() display.Writer {
+	comma, ext := ',', ".csv"
+	if *outputType == "tsv" {
+		comma, ext = '\t', ".tsv"
+	}
+	var (
+		edgesPath = filepath.Join(*tableDir, "edges"+ext)
+		nodesPath = filepath.Join(*tableDir, "nodes"+ext)
+	)
+	edges, err := os.Create(edgesPath)
+	fail(err)
+	nodes, err := os.Create(nodesPath)
+	fail(err)
+	logger.Infof("Write %s and %s", edgesPath, nodesPath)
+
+	var w display.Writer
+	if *useStat {
+		w = display.NewPackageTableWriter(edges, nodes, comma)
+	} else {
+		w = display.NewNodeTableWriter(edges, nodes, comma)
+	}
+	return &fileWriter{
+		Writer: w,
+		files:  []*os.File{edges, nodes},
+	}
+}
+
+// fileWriter closes the files after flush.
+type fileWriter struct {
+	display.Writer
+	files []*os.File
+}
+
+func (s *fileWriter) Flush() error {
+	err := s.Writer.Flush()
+	for _, f := range s.files {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("close %s: %w", f.Name(), cerr)
+		}
+	}
+	return err
+}
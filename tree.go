@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/berquerant/gotypegraph/display"
+)
+
+var (
+	treeRoot    = flag.String("tree.root", "", "Regexp to select the roots by full name when type is tree, the roots that no one uses by default.")
+	treeReverse = flag.Bool("tree.reverse", false, "Write the trees of the dependents instead of the dependencies when type is tree.")
+	treeDepth   = flag.Int("tree.depth", 0, "Max depth of the trees when type is tree, 0 means no limit.")
+)
+
+func newTreeWriter() display.Writer {
+	opt := []display.TreeOption{
+		display.WithTreeReverse(*treeReverse),
+		display.WithTreeDepth(*treeDepth),
+	}
+	if r := compileRegex(*treeRoot); r != nil {
+		opt = append(opt, display.WithTreeRoot(r))
+	}
+	if *useStat {
+		return display.NewPackageTreeWriter(os.Stdout, opt...)
+	}
+	return display.NewNodeTreeWriter(os.Stdout, opt...)
+}